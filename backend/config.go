@@ -21,6 +21,9 @@ type ServerConnection struct {
 	AltHostname string
 	Username    string
 	LegacyAuth  bool
+
+	// Subsonic chat user whose messages are shown as server announcements
+	AnnouncementSender string
 }
 
 type ServerConfig struct {
@@ -51,6 +54,11 @@ type AppConfig struct {
 	EnqueueBatchSize            int
 	Language                    string
 
+	// Subsonic stream URL token auth; see subsonic.Options.
+	// 0 keeps the client library's salt and token.
+	StreamAuthSaltLength      int
+	StreamAuthTokenTTLSeconds int
+
 	// Experimental - may be removed in future
 	FontNormalTTF string
 	FontBoldTTF   string
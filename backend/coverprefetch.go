@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"context"
+)
+
+const (
+	// max number of covers queued for prefetch; IDs past this
+	// in a PrefetchCoversForRange call are ignored
+	maxQueuedCoverPrefetches = 60
+	coverPrefetchWorkers     = 3
+)
+
+// PrefetchCoversForRange prefetches the thumbnails for the given cover IDs,
+// which should be those currently in (or near) the viewport of a scrolling
+// view, in priority order. Each call replaces the previous range: queued
+// prefetches for covers no longer in range are dropped and in-flight ones
+// are canceled, so that rapid scrolling doesn't pile up stale fetches.
+func (i *ImageManager) PrefetchCoversForRange(coverIDs []string) {
+	if len(coverIDs) > maxQueuedCoverPrefetches {
+		coverIDs = coverIDs[:maxQueuedCoverPrefetches]
+	}
+	inRange := make(map[string]bool, len(coverIDs))
+	for _, id := range coverIDs {
+		inRange[id] = true
+	}
+
+	i.prefetchLock.Lock()
+	defer i.prefetchLock.Unlock()
+	for id, cancel := range i.prefetchInFlight {
+		if !inRange[id] {
+			cancel()
+			delete(i.prefetchInFlight, id)
+		}
+	}
+	i.prefetchQueue = i.prefetchQueue[:0]
+	for _, id := range coverIDs {
+		if id == "" || i.prefetchInFlight[id] != nil {
+			continue
+		}
+		if _, ok := i.GetCoverThumbnailFromCache(id); ok {
+			continue
+		}
+		i.prefetchQueue = append(i.prefetchQueue, id)
+	}
+	for ; i.prefetchWorkers < coverPrefetchWorkers && i.prefetchWorkers < len(i.prefetchQueue); i.prefetchWorkers++ {
+		go i.runCoverPrefetchWorker()
+	}
+}
+
+// runCoverPrefetchWorker fetches queued covers, highest priority
+// first, until the queue is empty.
+func (i *ImageManager) runCoverPrefetchWorker() {
+	for {
+		i.prefetchLock.Lock()
+		if len(i.prefetchQueue) == 0 {
+			i.prefetchWorkers--
+			i.prefetchLock.Unlock()
+			return
+		}
+		id := i.prefetchQueue[0]
+		i.prefetchQueue = i.prefetchQueue[1:]
+		ctx, cancel := context.WithCancel(context.Background())
+		i.prefetchInFlight[id] = cancel
+		i.prefetchLock.Unlock()
+
+		_, _ = i.fetchAndCacheCoverFromDiskOrServer(ctx, id, i.thumbnailCache.DefaultTTL, nil)
+
+		i.prefetchLock.Lock()
+		if ctx.Err() == nil {
+			// not canceled and replaced
+			delete(i.prefetchInFlight, id)
+		}
+		i.prefetchLock.Unlock()
+		cancel()
+	}
+}
@@ -0,0 +1,151 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// max number of item IDs to send in a single SetFavorite call when flushing
+const favoriteQueueChunkSize = 100
+
+type favoriteItemType int
+
+const (
+	favoriteItemTrack favoriteItemType = iota
+	favoriteItemAlbum
+	favoriteItemArtist
+)
+
+type favoriteItem struct {
+	Type favoriteItemType `json:"type"`
+	ID   string           `json:"id"`
+}
+
+type favoriteIntent struct {
+	Item     favoriteItem `json:"item"`
+	Favorite bool         `json:"favorite"`
+}
+
+type serializedFavoriteQueue struct {
+	ServerID string           `json:"serverID"`
+	Intents  []favoriteIntent `json:"intents"`
+}
+
+// FavoriteQueue records favorite/unfavorite intents made while offline
+// so they can be replayed to the server on reconnect. Redundant toggles
+// are collapsed, e.g. favoriting and then unfavoriting an item is a no-op.
+// The queue is persisted to a JSON file so intents survive app restart.
+type FavoriteQueue struct {
+	mu       sync.Mutex
+	filepath string
+	serverID string
+	intents  []favoriteIntent
+}
+
+// NewFavoriteQueue creates a favorite queue for the given server, loading
+// any intents previously persisted to filepath for the same server.
+func NewFavoriteQueue(filepath, serverID string) *FavoriteQueue {
+	q := &FavoriteQueue{filepath: filepath, serverID: serverID}
+	if b, err := os.ReadFile(filepath); err == nil {
+		var saved serializedFavoriteQueue
+		if err := json.Unmarshal(b, &saved); err == nil && saved.ServerID == serverID {
+			q.intents = saved.Intents
+		}
+	}
+	return q
+}
+
+// Record adds the favorite intent for the given items to the queue.
+func (q *FavoriteQueue) Record(params mediaprovider.RatingFavoriteParameters, favorite bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, id := range params.TrackIDs {
+		q.record(favoriteItem{Type: favoriteItemTrack, ID: id}, favorite)
+	}
+	for _, id := range params.AlbumIDs {
+		q.record(favoriteItem{Type: favoriteItemAlbum, ID: id}, favorite)
+	}
+	for _, id := range params.ArtistIDs {
+		q.record(favoriteItem{Type: favoriteItemArtist, ID: id}, favorite)
+	}
+	return q.save()
+}
+
+func (q *FavoriteQueue) record(item favoriteItem, favorite bool) {
+	for i, in := range q.intents {
+		if in.Item == item {
+			if in.Favorite != favorite {
+				// opposite intent cancels out the pending one
+				q.intents = append(q.intents[:i], q.intents[i+1:]...)
+			}
+			return
+		}
+	}
+	q.intents = append(q.intents, favoriteIntent{Item: item, Favorite: favorite})
+}
+
+// Len returns the number of pending favorite intents.
+func (q *FavoriteQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.intents)
+}
+
+// Flush replays the pending intents to the server in chunks.
+// Intents that were successfully sent are removed from the queue,
+// and the remaining ones are kept to retry on the next flush.
+func (q *FavoriteQueue) Flush(mp mediaprovider.MediaProvider) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var errs []error
+	var remaining []favoriteIntent
+	for _, favorite := range []bool{true, false} {
+		pending := make([]favoriteIntent, 0, len(q.intents))
+		for _, in := range q.intents {
+			if in.Favorite == favorite {
+				pending = append(pending, in)
+			}
+		}
+		for len(pending) > 0 {
+			chunk := pending[:min(len(pending), favoriteQueueChunkSize)]
+			pending = pending[len(chunk):]
+			if err := mp.SetFavorite(toRatingFavoriteParameters(chunk), favorite); err != nil {
+				errs = append(errs, err)
+				remaining = append(remaining, chunk...)
+			}
+		}
+	}
+	q.intents = remaining
+	if err := q.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (q *FavoriteQueue) save() error {
+	b, _ := json.Marshal(serializedFavoriteQueue{
+		ServerID: q.serverID,
+		Intents:  q.intents,
+	})
+	return os.WriteFile(q.filepath, b, 0644)
+}
+
+func toRatingFavoriteParameters(intents []favoriteIntent) mediaprovider.RatingFavoriteParameters {
+	var params mediaprovider.RatingFavoriteParameters
+	for _, in := range intents {
+		switch in.Item.Type {
+		case favoriteItemTrack:
+			params.TrackIDs = append(params.TrackIDs, in.Item.ID)
+		case favoriteItemAlbum:
+			params.AlbumIDs = append(params.AlbumIDs, in.Item.ID)
+		case favoriteItemArtist:
+			params.ArtistIDs = append(params.ArtistIDs, in.Item.ID)
+		}
+	}
+	return params
+}
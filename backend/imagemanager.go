@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -46,6 +47,11 @@ type ImageManager struct {
 	filesWrittenSinceLastPrune bool
 
 	serverFetchSema chan interface{}
+
+	prefetchLock     sync.Mutex
+	prefetchQueue    []string // cover IDs, highest priority first
+	prefetchInFlight map[string]context.CancelFunc
+	prefetchWorkers  int
 }
 
 // NewImageManager returns a new ImageManager.
@@ -64,6 +70,7 @@ func NewImageManager(ctx context.Context, s *ServerManager, baseCacheDir string)
 		},
 		maxOnDiskCacheSizeBytes: defaultDiskCacheSizeBytes,
 		serverFetchSema:         make(chan interface{}, maxConcurrentServerFetches),
+		prefetchInFlight:        make(map[string]context.CancelFunc),
 	}
 	s.OnLogout(func() {
 		i.thumbnailCache.Clear()
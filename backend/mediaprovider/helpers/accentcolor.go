@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/cenkalti/dominantcolor"
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// size in pixels of the cover thumbnail used to compute accent colors
+const accentColorCoverSize = 64
+
+// DefaultAccentColor is the neutral color returned for albums without cover art.
+var DefaultAccentColor = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+
+// AlbumAccentColors computes and caches a per-album accent color,
+// derived from the dominant color of the album's cover art.
+type AlbumAccentColors struct {
+	mp mediaprovider.MediaProvider
+
+	mu    sync.Mutex
+	cache map[string]color.RGBA // keyed by album ID
+}
+
+func NewAlbumAccentColors(mp mediaprovider.MediaProvider) *AlbumAccentColors {
+	return &AlbumAccentColors{
+		mp:    mp,
+		cache: make(map[string]color.RGBA),
+	}
+}
+
+// GetAlbumAccentColor returns the accent color for the given album.
+// DefaultAccentColor is returned if the album has no cover art.
+func (a *AlbumAccentColors) GetAlbumAccentColor(albumID string) (color.RGBA, error) {
+	a.mu.Lock()
+	c, ok := a.cache[albumID]
+	a.mu.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	album, err := a.mp.GetAlbum(albumID)
+	if err != nil {
+		return DefaultAccentColor, err
+	}
+	c = DefaultAccentColor
+	if album.CoverArtID != "" {
+		img, err := a.mp.GetCoverArt(album.CoverArtID, accentColorCoverSize)
+		if err != nil {
+			return DefaultAccentColor, err
+		}
+		if img != nil {
+			c = dominantcolor.Find(img)
+		}
+	}
+
+	a.mu.Lock()
+	a.cache[albumID] = c
+	a.mu.Unlock()
+	return c, nil
+}
@@ -0,0 +1,271 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// GetAlbumTracksGrouped returns the album's tracks grouped by their primary
+// (first credited) artist, for per-artist display of compilations.
+// Groups are ordered by first appearance and tracks by disc and track number.
+// A single-artist album returns a single group.
+func GetAlbumTracksGrouped(mp mediaprovider.MediaProvider, albumID string) ([]mediaprovider.TrackGroup, error) {
+	album, err := mp.GetAlbum(albumID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading album tracks: %v", err.Error())
+	}
+	tracks := sortedByDiscAndTrack(album.Tracks)
+
+	var groups []mediaprovider.TrackGroup
+	groupIdx := make(map[string]int)
+	for _, tr := range tracks {
+		var name string
+		if len(tr.ArtistNames) > 0 {
+			name = tr.ArtistNames[0]
+		}
+		idx, ok := groupIdx[name]
+		if !ok {
+			idx = len(groups)
+			groupIdx[name] = idx
+			groups = append(groups, mediaprovider.TrackGroup{ArtistName: name})
+		}
+		groups[idx].Tracks = append(groups[idx].Tracks, tr)
+	}
+	return groups, nil
+}
+
+// returns a copy of the tracks stably sorted by disc and track number
+func sortedByDiscAndTrack(tracks []*mediaprovider.Track) []*mediaprovider.Track {
+	sorted := slices.Clone(tracks)
+	slices.SortStableFunc(sorted, func(a, b *mediaprovider.Track) int {
+		if a.DiscNumber != b.DiscNumber {
+			return a.DiscNumber - b.DiscNumber
+		}
+		return a.TrackNumber - b.TrackNumber
+	})
+	return sorted
+}
+
+// GetRecentlyPlayedAlbums returns up to count of the most recently
+// played albums matching the filter, most recent first.
+func GetRecentlyPlayedAlbums(mp mediaprovider.MediaProvider, count int, filter mediaprovider.AlbumFilter) ([]*mediaprovider.Album, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	if filter == nil {
+		filter = mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{})
+	}
+	iter := mp.IterateAlbums(mediaprovider.AlbumSortRecentlyPlayed, filter)
+	if iter == nil {
+		return nil, errors.New("recently played sort order not supported")
+	}
+	albums := make([]*mediaprovider.Album, 0, count)
+	for len(albums) < count {
+		al := iter.Next()
+		if al == nil {
+			break
+		}
+		albums = append(albums, al)
+	}
+	return albums, nil
+}
+
+// GetAlbumTracksByArtist returns the album's tracks that credit the given
+// artist, either as a track artist or as a contributor (composer),
+// in disc and track order. Returns an empty slice if the artist
+// does not appear on the album.
+func GetAlbumTracksByArtist(mp mediaprovider.MediaProvider, albumID, artistID string) ([]*mediaprovider.Track, error) {
+	album, err := mp.GetAlbum(albumID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading album tracks: %v", err.Error())
+	}
+	tracks := make([]*mediaprovider.Track, 0)
+	for _, tr := range sortedByDiscAndTrack(album.Tracks) {
+		if slices.Contains(tr.ArtistIDs, artistID) || slices.Contains(tr.ComposerIDs, artistID) {
+			tracks = append(tracks, tr)
+		}
+	}
+	return tracks, nil
+}
+
+// GetTracksAddedSince returns up to limit tracks added to the library after
+// since, ordered by album add date (newest first) and then disc and track order.
+// This is an approximation based on album add dates: the newest albums
+// are expanded into their tracks, so tracks added later to an existing
+// album are only included if the album's add date was updated by the server.
+func GetTracksAddedSince(mp mediaprovider.MediaProvider, since time.Time, limit int) ([]*mediaprovider.Track, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	iter := mp.IterateAlbums(mediaprovider.AlbumSortRecentlyAdded,
+		mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{}))
+	if iter == nil {
+		return nil, errors.New("recently added sort order not supported")
+	}
+	var tracks []*mediaprovider.Track
+	for len(tracks) < limit {
+		al := iter.Next()
+		// albums are sorted newest first, so stop at the first older album
+		if al == nil || !al.Created.After(since) {
+			break
+		}
+		album, err := mp.GetAlbum(al.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading album tracks: %v", err.Error())
+		}
+		tracks = append(tracks, sortedByDiscAndTrack(album.Tracks)...)
+	}
+	if len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks, nil
+}
+
+// max number of track IDs to send in a single SetFavorite call
+const setFavoriteChunkSize = 100
+
+// SetAlbumTracksFavorite favorites or unfavorites all of the album's tracks
+// (not the album itself), returning the number of tracks whose
+// favorite state was changed.
+func SetAlbumTracksFavorite(mp mediaprovider.MediaProvider, albumID string, favorite bool) (int, error) {
+	album, err := mp.GetAlbum(albumID)
+	if err != nil {
+		return 0, fmt.Errorf("error loading album tracks: %v", err.Error())
+	}
+	var ids []string
+	for _, tr := range album.Tracks {
+		if tr.Favorite != favorite {
+			ids = append(ids, tr.ID)
+		}
+	}
+	for i := 0; i < len(ids); i += setFavoriteChunkSize {
+		chunk := ids[i:min(i+setFavoriteChunkSize, len(ids))]
+		if err := mp.SetFavorite(mediaprovider.RatingFavoriteParameters{TrackIDs: chunk}, favorite); err != nil {
+			return i, fmt.Errorf("error setting favorite on album tracks: %v", err.Error())
+		}
+	}
+	return len(ids), nil
+}
+
+// GetAlbumGenres returns the union of the album-level genres and the
+// genres of each of the album's tracks, deduplicated case-insensitively.
+// Album-level genres come first, followed by track genres in track order.
+func GetAlbumGenres(mp mediaprovider.MediaProvider, albumID string) ([]string, error) {
+	album, err := mp.GetAlbum(albumID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading album tracks: %v", err.Error())
+	}
+	var genres []string
+	seen := make(map[string]bool)
+	add := func(g string) {
+		key := strings.ToLower(strings.TrimSpace(g))
+		if key != "" && !seen[key] {
+			seen[key] = true
+			genres = append(genres, g)
+		}
+	}
+	for _, g := range album.Genres {
+		add(g)
+	}
+	for _, tr := range sortedByDiscAndTrack(album.Tracks) {
+		for _, g := range tr.Genres {
+			add(g)
+		}
+	}
+	return genres, nil
+}
+
+// GetAdjacentAlbum returns the album immediately after (direction > 0) or
+// before (direction < 0) the given album in the given sort order, for
+// keyboard navigation. Returns mediaprovider.ErrNotFound if the album is
+// first or last, or is not found in the sort order.
+func GetAdjacentAlbum(mp mediaprovider.MediaProvider, albumID string, direction int, sortOrder string) (*mediaprovider.Album, error) {
+	if direction == 0 {
+		return nil, errors.New("direction must be non-zero")
+	}
+	iter := mp.IterateAlbums(sortOrder, mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{}))
+	if iter == nil {
+		return nil, fmt.Errorf("sort order %q not supported", sortOrder)
+	}
+	var prev *mediaprovider.Album
+	for al := iter.Next(); al != nil; al = iter.Next() {
+		if al.ID != albumID {
+			prev = al
+			continue
+		}
+		if direction < 0 {
+			if prev == nil {
+				return nil, mediaprovider.ErrNotFound
+			}
+			return prev, nil
+		}
+		if next := iter.Next(); next != nil {
+			return next, nil
+		}
+		return nil, mediaprovider.ErrNotFound
+	}
+	return nil, mediaprovider.ErrNotFound
+}
+
+// max difference in seconds between the durations of
+// tracks of the same title to be considered the same track
+const editionTrackDurationTolerance = 2
+
+// GetMergedAlbumEditions merges the tracks of several editions of the same
+// album, e.g. a standard and a deluxe edition. The first edition's metadata
+// and tracks, in disc and track order, come first, followed by the tracks
+// of each subsequent edition not already present. Tracks are considered the
+// same if their titles match case-insensitively (ignoring surrounding
+// whitespace) and their durations are within 2 seconds of each other.
+func GetMergedAlbumEditions(mp mediaprovider.MediaProvider, albumIDs []string) (*mediaprovider.AlbumWithTracks, error) {
+	if len(albumIDs) == 0 {
+		return nil, errors.New("no album editions given")
+	}
+	var merged *mediaprovider.AlbumWithTracks
+	seen := make(map[string][]int) // normalized title -> durations
+	for _, id := range albumIDs {
+		album, err := mp.GetAlbum(id)
+		if err != nil {
+			return nil, fmt.Errorf("error loading album tracks: %v", err.Error())
+		}
+		if merged == nil {
+			merged = &mediaprovider.AlbumWithTracks{Album: album.Album}
+		}
+		for _, tr := range sortedByDiscAndTrack(album.Tracks) {
+			title := strings.ToLower(strings.TrimSpace(tr.Title))
+			if slices.ContainsFunc(seen[title], func(d int) bool {
+				return abs(float64(d-tr.Duration)) <= editionTrackDurationTolerance
+			}) {
+				continue
+			}
+			seen[title] = append(seen[title], tr.Duration)
+			merged.Tracks = append(merged.Tracks, tr)
+		}
+	}
+	merged.TrackCount = len(merged.Tracks)
+	merged.Duration = 0
+	for _, tr := range merged.Tracks {
+		merged.Duration += tr.Duration
+	}
+	return merged, nil
+}
+
+// GetAlbumDurationCheck returns the album's server-reported duration
+// alongside the sum of its tracks' durations, to help spot albums with
+// bad duration metadata. The two normally match.
+func GetAlbumDurationCheck(mp mediaprovider.MediaProvider, albumID string) (reported, computed time.Duration, err error) {
+	album, err := mp.GetAlbum(albumID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error loading album tracks: %v", err.Error())
+	}
+	var sum int
+	for _, tr := range album.Tracks {
+		sum += tr.Duration
+	}
+	return time.Duration(album.Duration) * time.Second, time.Duration(sum) * time.Second, nil
+}
@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// how long the album snapshot used to look up release days is reused
+const releaseDaySnapshotTTL = 6 * time.Hour
+
+type monthDay struct {
+	month, day int
+}
+
+// AlbumReleaseDays looks up albums by the month and day of their release,
+// for "on this day" anniversaries. The library is scanned once into a
+// snapshot which is reused until it expires.
+type AlbumReleaseDays struct {
+	mp mediaprovider.MediaProvider
+
+	mu         sync.Mutex
+	snapshot   map[monthDay][]*mediaprovider.Album
+	snapshotAt time.Time
+}
+
+func NewAlbumReleaseDays(mp mediaprovider.MediaProvider) *AlbumReleaseDays {
+	return &AlbumReleaseDays{mp: mp}
+}
+
+// GetAlbumsReleasedOn returns the albums across all years whose original
+// release date (or, if unknown, reissue date) falls on the given month and day.
+// Albums whose release date is only known to the year are never returned.
+func (a *AlbumReleaseDays) GetAlbumsReleasedOn(month, day int) ([]*mediaprovider.Album, error) {
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return nil, fmt.Errorf("invalid release day %d/%d", month, day)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.snapshot == nil || time.Since(a.snapshotAt) > releaseDaySnapshotTTL {
+		snapshot, err := a.buildSnapshot()
+		if err != nil {
+			return nil, err
+		}
+		a.snapshot = snapshot
+		a.snapshotAt = time.Now()
+	}
+	return a.snapshot[monthDay{month, day}], nil
+}
+
+// Invalidate discards the snapshot so the next lookup rescans the library.
+func (a *AlbumReleaseDays) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.snapshot = nil
+}
+
+func (a *AlbumReleaseDays) buildSnapshot() (map[monthDay][]*mediaprovider.Album, error) {
+	iter := a.mp.IterateAlbums(mediaprovider.AlbumSortRecentlyAdded,
+		mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{}))
+	if iter == nil {
+		return nil, errors.New("recently added sort order not supported")
+	}
+	snapshot := make(map[monthDay][]*mediaprovider.Album)
+	for al := iter.Next(); al != nil; al = iter.Next() {
+		date := al.Date
+		if date.Month == nil || date.Day == nil {
+			date = al.ReissueDate
+		}
+		if date.Month == nil || date.Day == nil {
+			continue
+		}
+		md := monthDay{*date.Month, *date.Day}
+		snapshot[md] = append(snapshot[md], al)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// DoContext runs fn, returning early with ctx.Err() if ctx is canceled
+// before fn completes. fn itself is not interrupted: any request it has
+// sent keeps running in the background, and its eventual result is
+// discarded. Use it only with requests that are safe to abandon.
+func DoContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	type result struct {
+		val T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		done <- result{v, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case r := <-done:
+		return r.val, r.err
+	}
+}
+
+// NewContextIterator returns an iterator that yields the items of
+// iter until ctx is canceled, after which Next returns nil. A call to
+// iter.Next in progress when ctx is canceled is not interrupted.
+func NewContextIterator[M any](ctx context.Context, iter mediaprovider.MediaIterator[M]) mediaprovider.MediaIterator[M] {
+	return &contextIter[M]{ctx: ctx, iter: iter}
+}
+
+type contextIter[M any] struct {
+	ctx  context.Context
+	iter mediaprovider.MediaIterator[M]
+}
+
+func (c *contextIter[M]) Next() *M {
+	if c.ctx.Err() != nil {
+		return nil
+	}
+	m := c.iter.Next()
+	if c.ctx.Err() != nil {
+		return nil
+	}
+	return m
+}
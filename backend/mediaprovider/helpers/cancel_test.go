@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDoContext(t *testing.T) {
+	if v, err := DoContext(context.Background(), func() (int, error) { return 1, nil }); v != 1 || err != nil {
+		t.Errorf("got (%d, %v), want (1, nil)", v, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := DoContext(ctx, func() (int, error) {
+		close(started)
+		<-block
+		return 1, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestContextIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	iter := NewContextIterator(ctx, albumIterOf("a", "b", "c"))
+	if al := iter.Next(); al == nil || al.ID != "a" {
+		t.Fatalf("got %v, want album a", al)
+	}
+	cancel()
+	if al := iter.Next(); al != nil {
+		t.Errorf("got album %s after cancel, want nil", al.ID)
+	}
+}
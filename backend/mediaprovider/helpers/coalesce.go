@@ -0,0 +1,45 @@
+package helpers
+
+import "sync"
+
+// Coalescer shares a single in-flight call among concurrent callers
+// requesting the same key: while a call for a key is running, further
+// callers for that key wait for it and receive its result and error
+// rather than issuing a duplicate call. Callers thus share the returned
+// value and must not modify it. The zero value is ready to use.
+type Coalescer[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall[T]
+}
+
+type coalescedCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Do invokes fn for the key, or waits for and returns the result
+// of an invocation for the same key that is already in flight.
+func (c *Coalescer[T]) Do(key string, fn func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.val, call.err
+	}
+	if c.calls == nil {
+		c.calls = make(map[string]*coalescedCall[T])
+	}
+	call := &coalescedCall[T]{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	call.val, call.err = fn()
+	return call.val, call.err
+}
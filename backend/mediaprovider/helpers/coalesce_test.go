@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCoalescer(t *testing.T) {
+	var c Coalescer[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	errFailed := errors.New("failed")
+
+	const waiters = 10
+	var started, wg sync.WaitGroup
+	started.Add(waiters)
+	wg.Add(waiters)
+	results := make([]int, waiters)
+	errs := make([]error, waiters)
+	for i := 0; i < waiters; i++ {
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			results[i], errs[i] = c.Do("key", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, errFailed
+			})
+		}(i)
+	}
+	started.Wait()
+	close(release)
+	wg.Wait()
+
+	// goroutines that reached Do after the first call completed
+	// start a new call, so only require fewer calls than callers
+	if n := calls.Load(); n < 1 || n >= waiters {
+		t.Errorf("expected calls to be coalesced, got %d calls", n)
+	}
+	for i := range results {
+		if results[i] != 42 || !errors.Is(errs[i], errFailed) {
+			t.Errorf("caller %d: got %d, %v", i, results[i], errs[i])
+		}
+	}
+
+	// a completed call is not reused
+	v, err := c.Do("key", func() (int, error) { return 7, nil })
+	if v != 7 || err != nil {
+		t.Errorf("got %d, %v after previous call completed", v, err)
+	}
+}
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// DefaultBatchConcurrency is the default max number of concurrent
+// server requests issued by batch and fan-out operations.
+const DefaultBatchConcurrency = 5
+
+// BatchConcurrency returns the max number of concurrent server requests
+// batch operations should issue against the given provider. Providers may
+// configure it by implementing a BatchConcurrency() int method.
+func BatchConcurrency(mp mediaprovider.MediaProvider) int {
+	if b, ok := mp.(interface{ BatchConcurrency() int }); ok && b.BatchConcurrency() > 0 {
+		return b.BatchConcurrency()
+	}
+	return DefaultBatchConcurrency
+}
+
+// RunConcurrently invokes fn for each index in [0, n), with at most
+// limit invocations in flight at once. Once ctx is canceled, no further
+// invocations are started and ctx.Err() is returned after the in-flight
+// invocations have finished.
+func RunConcurrently(ctx context.Context, n, limit int, fn func(i int)) error {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(idx int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(idx)
+		}(i)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
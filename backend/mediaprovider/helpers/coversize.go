@@ -0,0 +1,27 @@
+package helpers
+
+import "slices"
+
+// DefaultCoverSizePresets are the cover art sizes (px) requested
+// when snapping arbitrary display sizes to a bounded set.
+var DefaultCoverSizePresets = []int{64, 128, 256, 300, 512, 768, 1024}
+
+// SnapCoverSize returns the smallest preset at least as large as targetPx,
+// or the largest preset if targetPx exceeds them all. A targetPx <= 0
+// (full size) is returned unchanged, as is targetPx if presets is empty.
+// Snapping requests to a few sizes improves hit rates of both the
+// local image cache and the server's resized image cache when display
+// sizes vary slightly, e.g. across screen densities.
+func SnapCoverSize(targetPx int, presets []int) int {
+	if targetPx <= 0 || len(presets) == 0 {
+		return targetPx
+	}
+	sorted := slices.Clone(presets)
+	slices.Sort(sorted)
+	for _, p := range sorted {
+		if p >= targetPx {
+			return p
+		}
+	}
+	return sorted[len(sorted)-1]
+}
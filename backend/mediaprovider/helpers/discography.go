@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// Release types in order of priority when choosing an album's primary type.
+// Secondary types (e.g. Live, Compilation) take precedence over the primary
+// types (Album, EP, Single) so that e.g. a live album is grouped under Live.
+var releaseTypePriority = []mediaprovider.ReleaseType{
+	mediaprovider.ReleaseTypeCompilation,
+	mediaprovider.ReleaseTypeLive,
+	mediaprovider.ReleaseTypeSoundtrack,
+	mediaprovider.ReleaseTypeRemix,
+	mediaprovider.ReleaseTypeDJMix,
+	mediaprovider.ReleaseTypeMixtape,
+	mediaprovider.ReleaseTypeDemo,
+	mediaprovider.ReleaseTypeAudiobook,
+	mediaprovider.ReleaseTypeAudioDrama,
+	mediaprovider.ReleaseTypeSpokenWord,
+	mediaprovider.ReleaseTypeInterview,
+	mediaprovider.ReleaseTypeFieldRecording,
+	mediaprovider.ReleaseTypeBroadcast,
+	mediaprovider.ReleaseTypeEP,
+	mediaprovider.ReleaseTypeSingle,
+	mediaprovider.ReleaseTypeAlbum,
+}
+
+// PrimaryReleaseType returns the highest-priority release type set in the
+// given bit field, or ReleaseTypeAlbum if none of the known types are set.
+func PrimaryReleaseType(types mediaprovider.ReleaseTypes) mediaprovider.ReleaseType {
+	for _, t := range releaseTypePriority {
+		if types&t != 0 {
+			return t
+		}
+	}
+	return mediaprovider.ReleaseTypeAlbum
+}
+
+// GetArtistDiscography returns the artist's albums grouped by their
+// primary release type (see PrimaryReleaseType), preserving the
+// order in which the albums are returned by the server.
+func GetArtistDiscography(mp mediaprovider.MediaProvider, artistID string) (map[mediaprovider.ReleaseTypes][]*mediaprovider.Album, error) {
+	artist, err := mp.GetArtist(artistID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting artist albums: %v", err.Error())
+	}
+	discography := make(map[mediaprovider.ReleaseTypes][]*mediaprovider.Album)
+	for _, al := range artist.Albums {
+		t := PrimaryReleaseType(al.ReleaseTypes)
+		discography[t] = append(discography[t], al)
+	}
+	return discography, nil
+}
@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"sync"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// DryRunOp records a mutating operation intercepted by a DryRunProvider.
+type DryRunOp struct {
+	Method        string // name of the intercepted MediaProvider method
+	ID            string // playlist ID, if applicable
+	ItemIDs       []string
+	TracksAdded   int
+	TracksRemoved int
+}
+
+// DryRunProvider wraps a MediaProvider, passing read methods through while
+// intercepting mutating methods: rather than reaching the server, they are
+// recorded in an inspectable log describing what would have happened,
+// e.g. to preview the impact of a bulk operation before committing it.
+// Optional capability interfaces of the wrapped provider (such as rating)
+// are not exposed, so no mutation can reach the server through the wrapper.
+type DryRunProvider struct {
+	mediaprovider.MediaProvider
+
+	mu  sync.Mutex
+	log []DryRunOp
+}
+
+func NewDryRunProvider(mp mediaprovider.MediaProvider) *DryRunProvider {
+	return &DryRunProvider{MediaProvider: mp}
+}
+
+// Log returns the operations recorded so far, oldest first.
+func (d *DryRunProvider) Log() []DryRunOp {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	log := make([]DryRunOp, len(d.log))
+	copy(log, d.log)
+	return log
+}
+
+// Reset clears the recorded operations.
+func (d *DryRunProvider) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = nil
+}
+
+func (d *DryRunProvider) record(op DryRunOp) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, op)
+}
+
+func (d *DryRunProvider) SetFavorite(params mediaprovider.RatingFavoriteParameters, favorite bool) error {
+	method := "Unfavorite"
+	if favorite {
+		method = "Favorite"
+	}
+	ids := append(append(append([]string{}, params.TrackIDs...), params.AlbumIDs...), params.ArtistIDs...)
+	d.record(DryRunOp{Method: method, ItemIDs: ids})
+	return nil
+}
+
+func (d *DryRunProvider) CreatePlaylist(name string, trackIDs []string) error {
+	d.record(DryRunOp{Method: "CreatePlaylist", ItemIDs: trackIDs, TracksAdded: len(trackIDs)})
+	return nil
+}
+
+func (d *DryRunProvider) EditPlaylist(id, name, description string, public bool) error {
+	d.record(DryRunOp{Method: "EditPlaylist", ID: id})
+	return nil
+}
+
+func (d *DryRunProvider) AddPlaylistTracks(id string, trackIDsToAdd []string) error {
+	d.record(DryRunOp{Method: "AddPlaylistTracks", ID: id, ItemIDs: trackIDsToAdd, TracksAdded: len(trackIDsToAdd)})
+	return nil
+}
+
+func (d *DryRunProvider) RemovePlaylistTracks(id string, trackIdxsToRemove []int) error {
+	op := DryRunOp{Method: "RemovePlaylistTracks", ID: id}
+	pl, err := d.MediaProvider.GetPlaylist(id)
+	if err != nil {
+		return err
+	}
+	for _, idx := range trackIdxsToRemove {
+		if idx >= 0 && idx < len(pl.Tracks) {
+			op.ItemIDs = append(op.ItemIDs, pl.Tracks[idx].ID)
+		}
+	}
+	op.TracksRemoved = len(op.ItemIDs)
+	d.record(op)
+	return nil
+}
+
+func (d *DryRunProvider) ReplacePlaylistTracks(id string, trackIDs []string) error {
+	pl, err := d.MediaProvider.GetPlaylist(id)
+	if err != nil {
+		return err
+	}
+	// count the tracks added and removed, treating
+	// each occurrence of a duplicated track separately
+	remaining := make(map[string]int, len(pl.Tracks))
+	for _, tr := range pl.Tracks {
+		remaining[tr.ID]++
+	}
+	added := 0
+	for _, trID := range trackIDs {
+		if remaining[trID] > 0 {
+			remaining[trID]--
+		} else {
+			added++
+		}
+	}
+	removed := 0
+	for _, n := range remaining {
+		removed += n
+	}
+	d.record(DryRunOp{Method: "ReplacePlaylistTracks", ID: id, ItemIDs: trackIDs,
+		TracksAdded: added, TracksRemoved: removed})
+	return nil
+}
+
+func (d *DryRunProvider) DeletePlaylist(id string) error {
+	pl, err := d.MediaProvider.GetPlaylist(id)
+	if err != nil {
+		return err
+	}
+	d.record(DryRunOp{Method: "DeletePlaylist", ID: id, TracksRemoved: len(pl.Tracks)})
+	return nil
+}
+
+func (d *DryRunProvider) TrackBeganPlayback(trackID string) error {
+	d.record(DryRunOp{Method: "TrackBeganPlayback", ItemIDs: []string{trackID}})
+	return nil
+}
+
+func (d *DryRunProvider) TrackEndedPlayback(trackID string, positionSecs int, submission bool) error {
+	d.record(DryRunOp{Method: "TrackEndedPlayback", ItemIDs: []string{trackID}})
+	return nil
+}
+
+func (d *DryRunProvider) RescanLibrary() error {
+	d.record(DryRunOp{Method: "RescanLibrary"})
+	return nil
+}
@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// articles ignored when comparing artist names,
+// matching the default ignoredArticles of Subsonic servers
+var ignoredArticles = []string{"the", "a", "an", "el", "la", "los", "las", "le", "les"}
+
+// FindDuplicateArtists scans all artists in the library and returns groups
+// of two or more artists whose names are the same after normalizing case and
+// whitespace and stripping leading (or trailing ", The"-style) articles.
+// E.g. "The Beatles", "Beatles" and "beatles, the" would be grouped together.
+// This is read-only; merging duplicates requires server support.
+func FindDuplicateArtists(ctx context.Context, mp mediaprovider.MediaProvider) ([][]*mediaprovider.Artist, error) {
+	iter := mp.IterateArtists(mediaprovider.ArtistSortNameAZ,
+		mediaprovider.NewArtistFilter(mediaprovider.ArtistFilterOptions{}))
+	if iter == nil {
+		return nil, nil
+	}
+
+	groups := make(map[string][]*mediaprovider.Artist)
+	var keys []string // preserve order of first appearance
+	for artist := iter.Next(); artist != nil; artist = iter.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		key := normalizeArtistName(artist.Name)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], artist)
+	}
+
+	var dupes [][]*mediaprovider.Artist
+	for _, key := range keys {
+		if g := groups[key]; len(g) > 1 {
+			dupes = append(dupes, g)
+		}
+	}
+	return dupes, nil
+}
+
+func normalizeArtistName(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) > 1 {
+		for _, a := range ignoredArticles {
+			if words[0] == a {
+				words = words[1:]
+				break
+			}
+			if last := len(words) - 1; words[last] == a && strings.HasSuffix(words[last-1], ",") {
+				words = words[:last]
+				words[last-1] = strings.TrimSuffix(words[last-1], ",")
+				break
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"math"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// max difference in album gain (dB) for tracks to be considered
+// to share the same album-level ReplayGain
+const gaplessAlbumGainTolerance = 0.01
+
+// TracksAreGaplessAdjacent returns true if b directly follows a on the same
+// album - the next track on the same disc, or the first track of the next
+// disc - and the two share compatible audio properties, i.e. sample rate
+// and album gain. Properties not reported by the server are not compared.
+func TracksAreGaplessAdjacent(a, b *mediaprovider.Track) bool {
+	if a == nil || b == nil || a.AlbumID == "" || a.AlbumID != b.AlbumID {
+		return false
+	}
+	sameDisc := a.DiscNumber == b.DiscNumber && b.TrackNumber == a.TrackNumber+1
+	nextDisc := b.DiscNumber == a.DiscNumber+1 && b.TrackNumber == 1
+	if a.TrackNumber == 0 || !(sameDisc || nextDisc) {
+		return false
+	}
+	if a.SampleRate > 0 && b.SampleRate > 0 && a.SampleRate != b.SampleRate {
+		return false
+	}
+	if a.ReplayGain != nil && b.ReplayGain != nil &&
+		math.Abs(a.ReplayGain.AlbumGain-b.ReplayGain.AlbumGain) > gaplessAlbumGainTolerance {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// GetHomeScreen fetches the rows of the home screen selected by opts
+// concurrently. Rows that fail to load have their Err set rather than
+// failing the whole payload; an error is only returned if every
+// requested row failed.
+func GetHomeScreen(mp mediaprovider.MediaProvider, opts mediaprovider.HomeScreenOptions) (*mediaprovider.HomeScreen, error) {
+	home := &mediaprovider.HomeScreen{}
+	noFilter := mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{})
+	favFilter := mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{ExcludeUnfavorited: true})
+	rows := []struct {
+		row       *mediaprovider.HomeScreenRow
+		count     int
+		sortOrder string
+		filter    mediaprovider.AlbumFilter
+	}{
+		{&home.RecentlyAdded, opts.RecentlyAddedCount, mediaprovider.AlbumSortRecentlyAdded, noFilter},
+		{&home.RecentlyPlayed, opts.RecentlyPlayedCount, mediaprovider.AlbumSortRecentlyPlayed, noFilter},
+		{&home.Random, opts.RandomCount, mediaprovider.AlbumSortRandom, noFilter},
+		{&home.Favorites, opts.FavoritesCount, "", favFilter},
+	}
+
+	RunConcurrently(context.Background(), len(rows), BatchConcurrency(mp), func(i int) {
+		r := rows[i]
+		if r.count <= 0 {
+			return
+		}
+		iter := mp.IterateAlbums(r.sortOrder, r.filter)
+		if iter == nil {
+			r.row.Err = errors.New("sort order not supported")
+			return
+		}
+		r.row.Albums = make([]*mediaprovider.Album, 0, r.count)
+		for al := iter.Next(); al != nil && len(r.row.Albums) < r.count; al = iter.Next() {
+			r.row.Albums = append(r.row.Albums, al)
+		}
+	})
+
+	var errs []error
+	requested := 0
+	for _, r := range rows {
+		if r.count > 0 {
+			requested++
+			if r.row.Err != nil {
+				errs = append(errs, r.row.Err)
+			}
+		}
+	}
+	if requested > 0 && len(errs) == requested {
+		return nil, errors.Join(errs...)
+	}
+	return home, nil
+}
@@ -190,6 +190,61 @@ func (r *randomAlbumIter) Next() *mediaprovider.Album {
 	return nil
 }
 
+// FilterTracks returns an iterator over the tracks matching searchQuery
+// (or all tracks if empty) that also match the given filter,
+// which is applied client-side.
+func FilterTracks(mp mediaprovider.MediaProvider, searchQuery string, filter mediaprovider.TrackFilter) mediaprovider.TrackIterator {
+	iter := mp.IterateTracks(searchQuery)
+	if filter == nil || filter.IsNil() {
+		return iter
+	}
+	return &filteredIter[mediaprovider.Track, mediaprovider.TrackFilterOptions]{iter: iter, filter: filter}
+}
+
+// filteredIter wraps an iterator, skipping items that don't match the filter.
+type filteredIter[M, F any] struct {
+	iter   mediaprovider.MediaIterator[M]
+	filter mediaprovider.MediaFilter[M, F]
+}
+
+func (f *filteredIter[M, F]) Next() *M {
+	for m := f.iter.Next(); m != nil; m = f.iter.Next() {
+		if f.filter.Matches(m) {
+			return m
+		}
+	}
+	return nil
+}
+
+// NewMergedAlbumIterator returns an iterator that yields the albums of each
+// of iters in turn, skipping any album already yielded by an earlier one.
+// Each iterator's own ordering is preserved, and only the IDs of
+// yielded albums are retained, so memory stays bounded by the result size.
+func NewMergedAlbumIterator(iters ...mediaprovider.AlbumIterator) mediaprovider.AlbumIterator {
+	return &mergedAlbumIter{iters: iters, seen: make(map[string]bool)}
+}
+
+type mergedAlbumIter struct {
+	iters []mediaprovider.AlbumIterator
+	seen  map[string]bool
+}
+
+func (m *mergedAlbumIter) Next() *mediaprovider.Album {
+	for len(m.iters) > 0 {
+		al := m.iters[0].Next()
+		if al == nil {
+			m.iters = m.iters[1:]
+			continue
+		}
+		if m.seen[al.ID] {
+			continue
+		}
+		m.seen[al.ID] = true
+		return al
+	}
+	return nil
+}
+
 type nilFilterOptions struct{}
 
 type nilFilter[M any] struct{}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+type sliceAlbumIter struct {
+	albums []*mediaprovider.Album
+}
+
+func (s *sliceAlbumIter) Next() *mediaprovider.Album {
+	if len(s.albums) == 0 {
+		return nil
+	}
+	al := s.albums[0]
+	s.albums = s.albums[1:]
+	return al
+}
+
+func albumIterOf(ids ...string) mediaprovider.AlbumIterator {
+	iter := &sliceAlbumIter{}
+	for _, id := range ids {
+		iter.albums = append(iter.albums, &mediaprovider.Album{ID: id})
+	}
+	return iter
+}
+
+func TestMergedAlbumIterator(t *testing.T) {
+	iter := NewMergedAlbumIterator(
+		albumIterOf("a", "b", "c"),
+		albumIterOf(),
+		albumIterOf("b", "d", "a", "e"),
+	)
+	var got []string
+	for al := iter.Next(); al != nil; al = iter.Next() {
+		got = append(got, al.ID)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if iter.Next() != nil {
+		t.Error("expected exhausted iterator to keep returning nil")
+	}
+}
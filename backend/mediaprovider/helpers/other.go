@@ -1,8 +1,10 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/dweymouth/supersonic/backend/mediaprovider"
 	"github.com/dweymouth/supersonic/sharedutil"
@@ -56,3 +58,78 @@ func GetTopTracksFallback(mp mediaprovider.MediaProvider, artistID string, count
 	}
 	return tracks, nil
 }
+
+// how many times the requested count of top tracks to fetch
+// before filtering, and the upper bound on tracks fetched
+const (
+	topTracksOverfetchFactor = 4
+	topTracksMaxFetch        = 200
+)
+
+// GetTopTracksFiltered returns up to count of the artist's top tracks that
+// match the filter, in popularity order. Since the filter is applied to a
+// bounded over-fetch of the top tracks, fewer than count tracks may be
+// returned if the filter is restrictive.
+func GetTopTracksFiltered(mp mediaprovider.MediaProvider, artist mediaprovider.Artist, count int, filter mediaprovider.TrackFilter) ([]*mediaprovider.Track, error) {
+	if filter == nil || filter.IsNil() {
+		return mp.GetTopTracks(artist, count)
+	}
+	if err := checkExplicitFilter(mp, filter.Options()); err != nil {
+		return nil, err
+	}
+	tracks, err := mp.GetTopTracks(artist, min(count*topTracksOverfetchFactor, topTracksMaxFetch))
+	if err != nil {
+		return nil, err
+	}
+	tracks = sharedutil.FilterSlice(tracks, filter.Matches)
+	if len(tracks) > count {
+		tracks = tracks[:count]
+	}
+	return tracks, nil
+}
+
+// SumTrackDurations returns the total duration of the given tracks, for
+// when only their IDs are known. Each distinct track is fetched once,
+// with at most BatchConcurrency(mp) requests in flight; tracks
+// appearing more than once are counted each time.
+func SumTrackDurations(mp mediaprovider.MediaProvider, trackIDs []string) (time.Duration, error) {
+	var unique []string
+	counts := make(map[string]int)
+	for _, id := range trackIDs {
+		if counts[id] == 0 {
+			unique = append(unique, id)
+		}
+		counts[id]++
+	}
+
+	durations := make([]int, len(unique))
+	errs := make([]error, len(unique))
+	RunConcurrently(context.Background(), len(unique), BatchConcurrency(mp), func(i int) {
+		tr, err := mp.GetTrack(unique[i])
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		durations[i] = tr.Duration
+	})
+	var total int
+	for i, id := range unique {
+		if errs[i] != nil {
+			return 0, fmt.Errorf("error getting track duration: %v", errs[i].Error())
+		}
+		total += durations[i] * counts[id]
+	}
+	return time.Duration(total) * time.Second, nil
+}
+
+// checkExplicitFilter returns ErrNotSupported if the filter options
+// filter on explicit content and the provider can't report it.
+func checkExplicitFilter(mp mediaprovider.MediaProvider, opts mediaprovider.TrackFilterOptions) error {
+	if !opts.ExcludeExplicit && !opts.RequireExplicit {
+		return nil
+	}
+	if e, ok := mp.(mediaprovider.SupportsExplicitMetadata); ok && e.ReportsExplicit() {
+		return nil
+	}
+	return mediaprovider.ErrNotSupported
+}
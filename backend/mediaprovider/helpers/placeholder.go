@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"hash/fnv"
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"github.com/deluan/sanitize"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const defaultPlaceholderCoverSize = 300
+
+// GeneratePlaceholderCover renders a square tile to use in place of missing
+// cover art. The tile's color is derived from a hash of the seed (typically
+// the item's name), and the initials of up to the first two words of
+// the seed are drawn on it, so the same seed always renders identically.
+func GeneratePlaceholderCover(seed string, size int) image.Image {
+	if size <= 0 {
+		size = defaultPlaceholderCoverSize
+	}
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(placeholderColor(seed)), image.Point{}, draw.Src)
+
+	initials := placeholderInitials(seed)
+	if initials == "" {
+		return img
+	}
+	// render the initials in the bitmap font at native size,
+	// then scale them up to about half the tile's width
+	face := basicfont.Face7x13
+	d := &font.Drawer{Face: face, Src: image.White}
+	textW := d.MeasureString(initials).Ceil()
+	textH := face.Height
+	if textW > size || textH > size {
+		return img // too small for legible initials
+	}
+	text := image.NewRGBA(image.Rect(0, 0, textW, textH))
+	d.Dst = text
+	d.Dot = fixed.P(0, face.Ascent)
+	d.DrawString(initials)
+
+	scale := max(1, min(size/2/textW, size/2/textH))
+	w, h := textW*scale, textH*scale
+	x, y := (size-w)/2, (size-h)/2
+	draw.NearestNeighbor.Scale(img, image.Rect(x, y, x+w, y+h), text, text.Bounds(), draw.Over, nil)
+	return img
+}
+
+// placeholderColor returns a muted color with the hue derived from the seed
+func placeholderColor(seed string) color.RGBA {
+	h := fnv.New32a()
+	h.Write([]byte(seed))
+	hue := float64(h.Sum32()%360) / 60
+	const s, v = 0.45, 0.6
+
+	c := v * s
+	x := c * (1 - abs(mod2(hue)-1))
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := v - c
+	return color.RGBA{
+		R: uint8((r + m) * 255),
+		G: uint8((g + m) * 255),
+		B: uint8((b + m) * 255),
+		A: 0xff,
+	}
+}
+
+// placeholderInitials returns the upper-cased first letter or digit
+// of up to the first two words of the seed. Letters that can't be
+// drawn in the bitmap font after removing accents are skipped.
+func placeholderInitials(seed string) string {
+	var sb strings.Builder
+	for _, word := range strings.Fields(sanitize.Accents(seed)) {
+		for _, r := range word {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				sb.WriteRune(unicode.ToUpper(r))
+				break
+			}
+		}
+		if sb.Len() == 2 {
+			break
+		}
+	}
+	return sb.String()
+}
+
+func mod2(f float64) float64 {
+	return f - float64(int(f/2)*2)
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
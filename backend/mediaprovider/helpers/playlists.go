@@ -0,0 +1,261 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+)
+
+// CreatePlaylistFromAlbum creates a new playlist containing all the tracks
+// of the given album and returns its ID. If name is empty, the album name is used.
+func CreatePlaylistFromAlbum(mp mediaprovider.MediaProvider, albumID, name string) (string, error) {
+	album, err := mp.GetAlbum(albumID)
+	if err != nil {
+		return "", fmt.Errorf("error loading album tracks: %v", err.Error())
+	}
+	if name == "" {
+		name = album.Name
+	}
+	return createPlaylistFromTracks(mp, name, album.Tracks)
+}
+
+// CreatePlaylistFromArtistTop creates a new playlist containing up to count
+// of the artist's top tracks and returns its ID. If name is empty,
+// a name is derived from the artist name.
+func CreatePlaylistFromArtistTop(mp mediaprovider.MediaProvider, artistID string, count int, name string) (string, error) {
+	artist, err := mp.GetArtist(artistID)
+	if err != nil {
+		return "", fmt.Errorf("error loading artist: %v", err.Error())
+	}
+	tracks, err := mp.GetTopTracks(artist.Artist, count)
+	if err != nil {
+		return "", fmt.Errorf("error loading top tracks: %v", err.Error())
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s - Top Tracks", artist.Name)
+	}
+	return createPlaylistFromTracks(mp, name, tracks)
+}
+
+// Creates the playlist and returns its ID. If the provider doesn't report
+// the ID of the newly created playlist, it is looked up afterwards by name
+// and track count, and an error is returned if it can't be found.
+func createPlaylistFromTracks(mp mediaprovider.MediaProvider, name string, tracks []*mediaprovider.Track) (string, error) {
+	if len(tracks) == 0 {
+		return "", errors.New("no tracks to add to playlist")
+	}
+	trackIDs := sharedutil.TracksToIDs(tracks)
+	if c, ok := mp.(mediaprovider.SupportsCreatePlaylistWithID); ok {
+		id, err := c.CreatePlaylistWithID(name, trackIDs)
+		if err != nil || id != "" {
+			return id, err
+		}
+	} else if err := mp.CreatePlaylist(name, trackIDs); err != nil {
+		return "", err
+	}
+	playlists, err := mp.GetPlaylists()
+	if err != nil {
+		return "", fmt.Errorf("playlist created, but error looking up its ID: %v", err.Error())
+	}
+	// iterate in reverse to prefer the most recently created match
+	for i := len(playlists) - 1; i >= 0; i-- {
+		if pl := playlists[i]; pl.Name == name && pl.TrackCount == len(tracks) {
+			return pl.ID, nil
+		}
+	}
+	return "", errors.New("playlist created, but its ID could not be determined")
+}
+
+// GetPlaylistArtists returns the distinct artists credited on the playlist's
+// tracks, in order of first appearance. All artists of multi-artist tracks
+// are included. Only the artist ID and name are populated.
+func GetPlaylistArtists(mp mediaprovider.MediaProvider, playlistID string) ([]*mediaprovider.Artist, error) {
+	pl, err := mp.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+	var artists []*mediaprovider.Artist
+	seen := make(map[string]bool)
+	for _, tr := range pl.Tracks {
+		for i, id := range tr.ArtistIDs {
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			artist := &mediaprovider.Artist{ID: id}
+			if i < len(tr.ArtistNames) {
+				artist.Name = tr.ArtistNames[i]
+			}
+			artists = append(artists, artist)
+		}
+	}
+	return artists, nil
+}
+
+// GetMergedPlaylistTracks returns the tracks of the given playlists
+// concatenated in the given order, preserving each playlist's track order.
+// If dedup is true, only the first occurrence of each track is kept.
+// Playlists that fail to load are skipped and their errors returned
+// alongside the tracks of the rest; only if every playlist fails
+// are no tracks returned.
+func GetMergedPlaylistTracks(mp mediaprovider.MediaProvider, playlistIDs []string, dedup bool) ([]*mediaprovider.Track, error) {
+	playlists := make([]*mediaprovider.PlaylistWithTracks, len(playlistIDs))
+	errs := make([]error, len(playlistIDs))
+	RunConcurrently(context.Background(), len(playlistIDs), BatchConcurrency(mp), func(i int) {
+		pl, err := mp.GetPlaylist(playlistIDs[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("error loading playlist %s: %v", playlistIDs[i], err.Error())
+			return
+		}
+		playlists[i] = pl
+	})
+
+	var tracks []*mediaprovider.Track
+	var loaded bool
+	seen := make(map[string]bool)
+	for _, pl := range playlists {
+		if pl == nil {
+			continue
+		}
+		loaded = true
+		for _, tr := range pl.Tracks {
+			if dedup {
+				if seen[tr.ID] {
+					continue
+				}
+				seen[tr.ID] = true
+			}
+			tracks = append(tracks, tr)
+		}
+	}
+	err := errors.Join(errs...)
+	if !loaded && err != nil {
+		return nil, err
+	}
+	return tracks, err
+}
+
+// GetPlaylistPage returns the playlist's metadata with only the window of
+// count tracks starting at offset, for lazily loading large playlists.
+// TrackCount holds the total number of tracks. Neither Subsonic nor
+// Jellyfin page getPlaylist, so the full playlist is fetched and sliced
+// client-side. A window past the end returns no tracks.
+func GetPlaylistPage(mp mediaprovider.MediaProvider, playlistID string, offset, count int) (*mediaprovider.PlaylistWithTracks, error) {
+	if offset < 0 || count < 0 {
+		return nil, errors.New("offset and count must not be negative")
+	}
+	pl, err := mp.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+	page := &mediaprovider.PlaylistWithTracks{Playlist: pl.Playlist}
+	page.TrackCount = len(pl.Tracks)
+	start := min(offset, len(pl.Tracks))
+	end := min(start+count, len(pl.Tracks))
+	page.Tracks = pl.Tracks[start:end:end]
+	return page, nil
+}
+
+// PlaylistChangedSince reports whether the playlist was modified after t,
+// using the playlist listing rather than fetching the playlist's tracks.
+// The listing may be cached by the provider for a short time. Returns
+// true if the server doesn't report modification times, and
+// mediaprovider.ErrNotFound if there is no playlist with the given ID.
+func PlaylistChangedSince(mp mediaprovider.MediaProvider, playlistID string, t time.Time) (bool, error) {
+	playlists, err := mp.GetPlaylists()
+	if err != nil {
+		return false, err
+	}
+	for _, pl := range playlists {
+		if pl.ID == playlistID {
+			return pl.ChangedSince.IsZero() || pl.ChangedSince.After(t), nil
+		}
+	}
+	return false, mediaprovider.ErrNotFound
+}
+
+// ValidatePlaylist checks each of the playlist's track references against
+// the server, returning the IDs of the tracks that exist and of those that
+// are missing, each in playlist order.
+func ValidatePlaylist(mp mediaprovider.MediaProvider, playlistID string) (validTrackIDs, missingTrackIDs []string, err error) {
+	pl, err := mp.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, nil, err
+	}
+	missing, err := findMissingTracks(mp, pl.Tracks)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, tr := range pl.Tracks {
+		if missing[i] {
+			missingTrackIDs = append(missingTrackIDs, tr.ID)
+		} else {
+			validTrackIDs = append(validTrackIDs, tr.ID)
+		}
+	}
+	return validTrackIDs, missingTrackIDs, nil
+}
+
+// RepairPlaylist removes the references to tracks missing from the server
+// from the playlist. If the provider has already dropped some missing
+// entries from the playlist it returns, track indexes no longer match the
+// server's, so the playlist's tracks are replaced with the valid ones instead.
+func RepairPlaylist(mp mediaprovider.MediaProvider, playlistID string) error {
+	pl, err := mp.GetPlaylist(playlistID)
+	if err != nil {
+		return err
+	}
+	missing, err := findMissingTracks(mp, pl.Tracks)
+	if err != nil {
+		return err
+	}
+	if pl.TrackCount > len(pl.Tracks) {
+		valid := make([]string, 0, len(pl.Tracks))
+		for i, tr := range pl.Tracks {
+			if !missing[i] {
+				valid = append(valid, tr.ID)
+			}
+		}
+		return mp.ReplacePlaylistTracks(playlistID, valid)
+	}
+	var idxs []int
+	for i := range pl.Tracks {
+		if missing[i] {
+			idxs = append(idxs, i)
+		}
+	}
+	if len(idxs) == 0 {
+		return nil
+	}
+	return mp.RemovePlaylistTracks(playlistID, idxs)
+}
+
+// findMissingTracks reports, per index, whether the track no longer exists
+// on the server. Tracks are checked concurrently, bounded by BatchConcurrency.
+// Only a lookup returning mediaprovider.ErrNotFound or a nil track counts as
+// missing; any other lookup error is returned, so that a transient failure
+// can't get a valid track removed from the playlist.
+func findMissingTracks(mp mediaprovider.MediaProvider, tracks []*mediaprovider.Track) ([]bool, error) {
+	missing := make([]bool, len(tracks))
+	errs := make([]error, len(tracks))
+	RunConcurrently(context.Background(), len(tracks), BatchConcurrency(mp), func(i int) {
+		if tracks[i].Missing {
+			missing[i] = true
+			return
+		}
+		tr, err := mp.GetTrack(tracks[i].ID)
+		if err != nil && !errors.Is(err, mediaprovider.ErrNotFound) {
+			errs[i] = err
+			return
+		}
+		missing[i] = err != nil || tr == nil
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("error validating playlist tracks: %w", err)
+	}
+	return missing, nil
+}
@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// trackLookupProvider implements GetTrack from a fixed table of lookup errors.
+type trackLookupProvider struct {
+	mediaprovider.MediaProvider
+	errs map[string]error
+}
+
+func (p *trackLookupProvider) GetTrack(id string) (*mediaprovider.Track, error) {
+	if err := p.errs[id]; err != nil {
+		return nil, err
+	}
+	return &mediaprovider.Track{ID: id}, nil
+}
+
+func TestFindMissingTracks(t *testing.T) {
+	tracks := []*mediaprovider.Track{{ID: "a"}, {ID: "b"}, {ID: "c", Missing: true}}
+	notFound := fmt.Errorf("%w: Error #70: song not found", mediaprovider.ErrNotFound)
+
+	mp := &trackLookupProvider{errs: map[string]error{"b": notFound}}
+	missing, err := findMissingTracks(mp, tracks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []bool{false, true, true}; !slices.Equal(missing, want) {
+		t.Errorf("got %v, want %v", missing, want)
+	}
+
+	// a transient failure must not mark the track as missing
+	errTimeout := errors.New("timeout")
+	mp.errs["a"] = errTimeout
+	if _, err := findMissingTracks(mp, tracks); !errors.Is(err, errTimeout) {
+		t.Errorf("got error %v, want %v", err, errTimeout)
+	}
+}
+
+// playlistCreator implements CreatePlaylist and GetPlaylists over a slice,
+// optionally reporting created playlists' IDs.
+type playlistCreator struct {
+	mediaprovider.MediaProvider
+	playlists []*mediaprovider.Playlist
+}
+
+func (p *playlistCreator) CreatePlaylist(name string, trackIDs []string) error {
+	p.playlists = append(p.playlists, &mediaprovider.Playlist{
+		ID: fmt.Sprint(len(p.playlists) + 1), Name: name, TrackCount: len(trackIDs),
+	})
+	return nil
+}
+
+func (p *playlistCreator) GetPlaylists() ([]*mediaprovider.Playlist, error) {
+	return p.playlists, nil
+}
+
+type playlistCreatorWithID struct {
+	playlistCreator
+}
+
+func (p *playlistCreatorWithID) CreatePlaylistWithID(name string, trackIDs []string) (string, error) {
+	p.CreatePlaylist(name, trackIDs)
+	return p.playlists[len(p.playlists)-1].ID, nil
+}
+
+// noMatchCreator creates playlists with a different track count than requested,
+// as a server may after dropping a missing track.
+type noMatchCreator struct {
+	*playlistCreator
+}
+
+func (p *noMatchCreator) CreatePlaylist(name string, trackIDs []string) error {
+	return p.playlistCreator.CreatePlaylist(name, trackIDs[1:])
+}
+
+func TestCreatePlaylistFromTracks(t *testing.T) {
+	tracks := []*mediaprovider.Track{{ID: "a"}, {ID: "b"}}
+
+	withID := &playlistCreatorWithID{}
+	withID.playlists = []*mediaprovider.Playlist{{ID: "1", Name: "Mix", TrackCount: 2}}
+	if id, err := createPlaylistFromTracks(withID, "Mix", tracks); err != nil || id != "2" {
+		t.Errorf("with ID: got %q, %v, want the created playlist", id, err)
+	}
+
+	lookup := &playlistCreator{}
+	if id, err := createPlaylistFromTracks(lookup, "Mix", tracks); err != nil || id != "1" {
+		t.Errorf("lookup: got %q, %v, want the matching playlist", id, err)
+	}
+
+	noMatch := &noMatchCreator{&playlistCreator{}}
+	if _, err := createPlaylistFromTracks(noMatch, "Mix", tracks); err == nil {
+		t.Error("expected an error when the created playlist can't be found")
+	}
+}
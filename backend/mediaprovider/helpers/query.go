@@ -0,0 +1,277 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/deluan/sanitize"
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+const (
+	queryTracksMaxResults = 500
+	// upper bound on tracks examined client-side for queries
+	// that can't be narrowed down by a server-side list
+	queryTracksMaxScanned = 10000
+)
+
+// QueryParseError is returned by ParseTrackQuery for an invalid expression.
+type QueryParseError struct {
+	Term   string
+	Reason string
+}
+
+func (e QueryParseError) Error() string {
+	return fmt.Sprintf("invalid query term %q: %s", e.Term, e.Reason)
+}
+
+// TrackQuery is a parsed advanced query expression.
+type TrackQuery struct {
+	Filter     mediaprovider.TrackFilterOptions
+	SearchTerm string // free text not bound to a field
+}
+
+// ParseTrackQuery parses an expression made up of whitespace-separated
+// field:value terms and free search text, e.g.
+//
+//	genre:jazz year:1960..1970 rating:>=4 artist:"Miles Davis" blue
+//
+// Supported fields are genre (may be repeated to match any), year and
+// rating (a single value, a lo..hi range with either end optional, or a
+// comparison with <, <=, >, >=), artist, favorite and explicit (true/false).
+func ParseTrackQuery(expr string) (*TrackQuery, error) {
+	terms, err := splitQueryTerms(expr)
+	if err != nil {
+		return nil, err
+	}
+	q := &TrackQuery{}
+	var search []string
+	for _, term := range terms {
+		field, value, ok := strings.Cut(term, ":")
+		if !ok {
+			search = append(search, term)
+			continue
+		}
+		field = strings.ToLower(field)
+		if value == "" {
+			return nil, QueryParseError{Term: term, Reason: "missing value"}
+		}
+		switch field {
+		case "genre":
+			q.Filter.Genres = append(q.Filter.Genres, value)
+		case "artist":
+			if q.Filter.ArtistName != "" {
+				return nil, QueryParseError{Term: term, Reason: "artist may only be given once"}
+			}
+			q.Filter.ArtistName = value
+		case "year":
+			minVal, maxVal, err := parseQueryRange(value, 0, 0)
+			if err != nil {
+				return nil, QueryParseError{Term: term, Reason: err.Error()}
+			}
+			q.Filter.MinYear, q.Filter.MaxYear = minVal, maxVal
+		case "rating":
+			minVal, maxVal, err := parseQueryRange(value, 1, 5)
+			if err != nil {
+				return nil, QueryParseError{Term: term, Reason: err.Error()}
+			}
+			q.Filter.MinRating, q.Filter.MaxRating = minVal, maxVal
+		case "favorite":
+			b, err := parseQueryBool(value)
+			if err != nil {
+				return nil, QueryParseError{Term: term, Reason: err.Error()}
+			}
+			q.Filter.ExcludeFavorited, q.Filter.ExcludeUnfavorited = !b, b
+		case "explicit":
+			b, err := parseQueryBool(value)
+			if err != nil {
+				return nil, QueryParseError{Term: term, Reason: err.Error()}
+			}
+			q.Filter.ExcludeExplicit, q.Filter.RequireExplicit = !b, b
+		default:
+			return nil, QueryParseError{Term: term, Reason: "unknown field"}
+		}
+	}
+	q.SearchTerm = strings.Join(search, " ")
+	return q, nil
+}
+
+// QueryTracks returns up to 500 tracks matching the advanced query expression
+// (see ParseTrackQuery). The most selective server-side listing available is
+// used to fetch candidates - genre tracks, albums by year, or a search -
+// and the remaining predicates are applied client-side.
+// Returns ErrNotSupported for an explicit term if the provider
+// doesn't report explicit content.
+func QueryTracks(mp mediaprovider.MediaProvider, expr string) ([]*mediaprovider.Track, error) {
+	q, err := ParseTrackQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExplicitFilter(mp, q.Filter); err != nil {
+		return nil, err
+	}
+	filter := mediaprovider.NewTrackFilter(q.Filter)
+	searchTerms := strings.Fields(strings.ToLower(sanitize.Accents(q.SearchTerm)))
+
+	var results []*mediaprovider.Track
+	// returns false once enough results have been collected
+	add := func(tr *mediaprovider.Track) bool {
+		if filter.Matches(tr) && trackMatchesTerms(tr, searchTerms) {
+			results = append(results, tr)
+		}
+		return len(results) < queryTracksMaxResults
+	}
+
+	if mf, ok := mp.(mediaprovider.SupportsMusicFolderScoping); ok && len(q.Filter.Genres) == 1 {
+		tracks, err := mf.GetGenreTracks(q.Filter.Genres[0], queryTracksMaxScanned, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, tr := range tracks {
+			if !add(tr) {
+				break
+			}
+		}
+		return results, nil
+	}
+
+	if q.Filter.MinYear > 0 || q.Filter.MaxYear > 0 {
+		albumFilter := mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{
+			MinYear: q.Filter.MinYear,
+			MaxYear: q.Filter.MaxYear,
+			Genres:  q.Filter.Genres,
+		})
+		iter := mp.IterateAlbums(mediaprovider.AlbumSortYearAscending, albumFilter)
+		for al := iter.Next(); al != nil; al = iter.Next() {
+			if q.Filter.MaxYear > 0 && al.YearOrZero() > q.Filter.MaxYear {
+				break // sorted by year, no more matches
+			}
+			album, err := mp.GetAlbum(al.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, tr := range album.Tracks {
+				if !add(tr) {
+					return results, nil
+				}
+			}
+		}
+		return results, nil
+	}
+
+	iter := mp.IterateTracks(q.SearchTerm)
+	for i, tr := 0, iter.Next(); tr != nil && i < queryTracksMaxScanned; i, tr = i+1, iter.Next() {
+		if !add(tr) {
+			break
+		}
+	}
+	return results, nil
+}
+
+// searchTerms should be lowercased and accent-stripped
+func trackMatchesTerms(tr *mediaprovider.Track, searchTerms []string) bool {
+	if len(searchTerms) == 0 {
+		return true
+	}
+	name := strings.ToLower(sanitize.Accents(
+		strings.Join(append([]string{tr.Title, tr.Album}, tr.ArtistNames...), " ")))
+	return AllTermsMatch(name, searchTerms)
+}
+
+// splitQueryTerms splits on whitespace, keeping double-quoted
+// sections (which may contain spaces) together without the quotes.
+func splitQueryTerms(expr string) ([]string, error) {
+	var terms []string
+	var sb strings.Builder
+	inQuote := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if sb.Len() > 0 {
+				terms = append(terms, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, QueryParseError{Term: expr, Reason: "unterminated quote"}
+	}
+	if sb.Len() > 0 {
+		terms = append(terms, sb.String())
+	}
+	return terms, nil
+}
+
+// parseQueryRange parses "n", "lo..hi", "lo..", "..hi", "<n", "<=n", ">n", ">=n"
+// into an inclusive [min, max] range where 0 means unbounded.
+// If lo < hi, values are bounds-checked against [lo, hi].
+func parseQueryRange(value string, lo, hi int) (minVal, maxVal int, err error) {
+	atoi := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+		if lo < hi && (n < lo || n > hi) {
+			return 0, fmt.Errorf("%d is out of range %d-%d", n, lo, hi)
+		}
+		return n, nil
+	}
+
+	switch {
+	case strings.Contains(value, ".."):
+		from, to, _ := strings.Cut(value, "..")
+		if from == "" && to == "" {
+			return 0, 0, fmt.Errorf("empty range")
+		}
+		if from != "" {
+			if minVal, err = atoi(from); err != nil {
+				return 0, 0, err
+			}
+		}
+		if to != "" {
+			if maxVal, err = atoi(to); err != nil {
+				return 0, 0, err
+			}
+		}
+		if maxVal > 0 && minVal > maxVal {
+			return 0, 0, fmt.Errorf("range start is after end")
+		}
+	case strings.HasPrefix(value, ">="):
+		minVal, err = atoi(value[2:])
+	case strings.HasPrefix(value, "<="):
+		maxVal, err = atoi(value[2:])
+	case strings.HasPrefix(value, ">"):
+		if minVal, err = atoi(value[1:]); err == nil {
+			minVal++
+		}
+	case strings.HasPrefix(value, "<"):
+		if maxVal, err = atoi(value[1:]); err == nil {
+			maxVal--
+		}
+	default:
+		minVal, err = atoi(value)
+		maxVal = minVal
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if (lo < hi && minVal > hi) || (strings.HasPrefix(value, "<") && maxVal < 1) {
+		return 0, 0, fmt.Errorf("range matches nothing")
+	}
+	return minVal, maxVal, nil
+}
+
+func parseQueryBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not true or false", value)
+}
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+func TestParseTrackQuery(t *testing.T) {
+	q, err := ParseTrackQuery(`genre:jazz year:1960..1970 rating:>=4 artist:"Miles Davis" favorite:yes kind of blue`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f := q.Filter
+	if !slices.Equal(f.Genres, []string{"jazz"}) {
+		t.Errorf("genres: got %v", f.Genres)
+	}
+	if f.MinYear != 1960 || f.MaxYear != 1970 {
+		t.Errorf("year: got %d..%d", f.MinYear, f.MaxYear)
+	}
+	if f.MinRating != 4 || f.MaxRating != 0 {
+		t.Errorf("rating: got %d..%d", f.MinRating, f.MaxRating)
+	}
+	if f.ArtistName != "Miles Davis" {
+		t.Errorf("artist: got %q", f.ArtistName)
+	}
+	if !f.ExcludeUnfavorited || f.ExcludeFavorited {
+		t.Error("favorite:yes should exclude unfavorited")
+	}
+	if q.SearchTerm != "kind of blue" {
+		t.Errorf("search term: got %q", q.SearchTerm)
+	}
+
+	q, err = ParseTrackQuery("rating:<3 year:..1999 explicit:false")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.Filter.MinRating != 0 || q.Filter.MaxRating != 2 {
+		t.Errorf("rating: got %d..%d", q.Filter.MinRating, q.Filter.MaxRating)
+	}
+	if q.Filter.MinYear != 0 || q.Filter.MaxYear != 1999 {
+		t.Errorf("year: got %d..%d", q.Filter.MinYear, q.Filter.MaxYear)
+	}
+	if !q.Filter.ExcludeExplicit {
+		t.Error("explicit:false should exclude explicit")
+	}
+}
+
+func TestParseTrackQuery_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"mood:happy",
+		"year:1970..1960",
+		"year:abc",
+		"rating:6",
+		"rating:<1",
+		"favorite:maybe",
+		"genre:",
+		`artist:"Miles`,
+	} {
+		_, err := ParseTrackQuery(expr)
+		var perr QueryParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: expected QueryParseError, got %v", expr, err)
+		}
+	}
+}
+
+func TestQueryTracks_ExplicitNotReported(t *testing.T) {
+	// the embedded nil provider panics if QueryTracks gets as far as a server request
+	mp := &trackLookupProvider{}
+	for _, expr := range []string{"explicit:true", "explicit:false blue"} {
+		if _, err := QueryTracks(mp, expr); !errors.Is(err, mediaprovider.ErrNotSupported) {
+			t.Errorf("%s: got error %v, want ErrNotSupported", expr, err)
+		}
+	}
+}
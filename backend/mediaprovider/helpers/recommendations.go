@@ -0,0 +1,128 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// max number of tracks from the end of the queue used as seeds
+const maxRecommendationSeeds = 5
+
+// GetQueueRecommendations returns up to count tracks similar to the ones in
+// the given queue, for automatically continuing playback once it runs out.
+// The artists of the last few queued tracks are used as seeds, and the
+// similar tracks for each seed artist are interleaved. Tracks already in
+// the queue are excluded and results are deduplicated.
+func GetQueueRecommendations(ctx context.Context, mp mediaprovider.MediaProvider, currentTrackIDs []string, count int) ([]*mediaprovider.Track, error) {
+	if count <= 0 || len(currentTrackIDs) == 0 {
+		return nil, nil
+	}
+
+	// sample seed tracks from the end of the queue, most recent first
+	var seedIDs []string
+	seen := make(map[string]bool)
+	for i := len(currentTrackIDs) - 1; i >= 0 && len(seedIDs) < maxRecommendationSeeds; i-- {
+		if id := currentTrackIDs[i]; !seen[id] {
+			seen[id] = true
+			seedIDs = append(seedIDs, id)
+		}
+	}
+
+	seedTracks := make([]*mediaprovider.Track, len(seedIDs))
+	err := RunConcurrently(ctx, len(seedIDs), BatchConcurrency(mp), func(i int) {
+		if tr, err := mp.GetTrack(seedIDs[i]); err == nil {
+			seedTracks[i] = tr
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var artistIDs []string
+	seenArtists := make(map[string]bool)
+	for _, tr := range seedTracks {
+		if tr == nil || len(tr.ArtistIDs) == 0 || tr.ArtistIDs[0] == "" {
+			continue
+		}
+		if id := tr.ArtistIDs[0]; !seenArtists[id] {
+			seenArtists[id] = true
+			artistIDs = append(artistIDs, id)
+		}
+	}
+	if len(artistIDs) == 0 {
+		return nil, nil
+	}
+
+	queued := make(map[string]bool, len(currentTrackIDs))
+	for _, id := range currentTrackIDs {
+		queued[id] = true
+	}
+	return getSimilarTracksInterleaved(ctx, mp, artistIDs, count, func(tr *mediaprovider.Track) bool {
+		return !queued[tr.ID]
+	})
+}
+
+// GetSimilarTracksMulti returns up to count tracks similar to any of the given
+// artists, for building a blended radio. The similar tracks for each artist
+// are interleaved fairly and deduplicated. Artists with no similar tracks
+// are skipped; an error is only returned if every request failed.
+func GetSimilarTracksMulti(mp mediaprovider.MediaProvider, artistIDs []string, count int) ([]*mediaprovider.Track, error) {
+	if count <= 0 || len(artistIDs) == 0 {
+		return nil, nil
+	}
+	return getSimilarTracksInterleaved(context.Background(), mp, artistIDs, count, nil)
+}
+
+func getSimilarTracksInterleaved(ctx context.Context, mp mediaprovider.MediaProvider, artistIDs []string, count int, include func(*mediaprovider.Track) bool) ([]*mediaprovider.Track, error) {
+	similar := make([][]*mediaprovider.Track, len(artistIDs))
+	errs := make([]error, len(artistIDs))
+	err := RunConcurrently(ctx, len(artistIDs), BatchConcurrency(mp), func(i int) {
+		similar[i], errs[i] = mp.GetSimilarTracks(artistIDs[i], count)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := interleaveTracks(similar, count, include)
+	if len(result) == 0 {
+		// only report an error if every request failed
+		for _, e := range errs {
+			if e == nil {
+				return result, nil
+			}
+		}
+		return nil, errs[0]
+	}
+	return result, nil
+}
+
+// interleaveTracks merges the given track lists round-robin, skipping
+// duplicate IDs and tracks not accepted by include, until count tracks
+// have been collected or all lists are exhausted.
+func interleaveTracks(lists [][]*mediaprovider.Track, count int, include func(*mediaprovider.Track) bool) []*mediaprovider.Track {
+	var result []*mediaprovider.Track
+	seen := make(map[string]bool)
+	for i := 0; len(result) < count; i++ {
+		var more bool
+		for _, l := range lists {
+			if i >= len(l) {
+				continue
+			}
+			more = true
+			tr := l[i]
+			if tr == nil || seen[tr.ID] || (include != nil && !include(tr)) {
+				continue
+			}
+			seen[tr.ID] = true
+			result = append(result, tr)
+			if len(result) == count {
+				break
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy configures how Retry re-attempts a failed operation.
+// The zero value makes a single attempt.
+type RetryPolicy struct {
+	// Max number of attempts, including the first. Values < 2 disable retries.
+	MaxAttempts int
+
+	// Delay before the first retry, doubled for each subsequent one.
+	Backoff time.Duration
+}
+
+// Retry invokes fn until it succeeds, it returns an error that isRetryable
+// rejects, or the policy's attempts are exhausted, waiting with exponential
+// backoff between attempts. fn must be idempotent. If ctx is canceled while
+// waiting, Retry stops and returns ctx.Err(). A nil isRetryable retries all errors.
+func Retry[T any](ctx context.Context, policy RetryPolicy, isRetryable func(error) bool, fn func() (T, error)) (T, error) {
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		res, err := fn()
+		if err == nil || attempt >= policy.MaxAttempts || (isRetryable != nil && !isRetryable(err)) {
+			return res, err
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			var zero T
+			return zero, ctx.Err()
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
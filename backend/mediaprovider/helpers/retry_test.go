@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	isRetryable := func(err error) bool { return err == errTransient }
+
+	failing := func(errs ...error) (func() (int, error), *int) {
+		calls := 0
+		return func() (int, error) {
+			calls++
+			if calls <= len(errs) {
+				return 0, errs[calls-1]
+			}
+			return calls, nil
+		}, &calls
+	}
+
+	fn, calls := failing(errTransient, errTransient)
+	if res, err := Retry(context.Background(), policy, isRetryable, fn); err != nil || res != 3 {
+		t.Errorf("got (%d, %v), want success on 3rd attempt", res, err)
+	}
+
+	fn, calls = failing(errTransient, errTransient, errTransient)
+	if _, err := Retry(context.Background(), policy, isRetryable, fn); err != errTransient || *calls != 3 {
+		t.Errorf("got %v after %d calls, want errTransient after 3", err, *calls)
+	}
+
+	fn, calls = failing(errFatal)
+	if _, err := Retry(context.Background(), policy, isRetryable, fn); err != errFatal || *calls != 1 {
+		t.Errorf("got %v after %d calls, want errFatal after 1", err, *calls)
+	}
+
+	fn, calls = failing(errTransient)
+	if _, err := Retry(context.Background(), RetryPolicy{}, isRetryable, fn); err != errTransient || *calls != 1 {
+		t.Errorf("zero policy: got %v after %d calls, want errTransient after 1", err, *calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fn, calls = failing(errTransient)
+	slow := RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}
+	if _, err := Retry(ctx, slow, isRetryable, fn); !errors.Is(err, context.Canceled) || *calls != 1 {
+		t.Errorf("canceled: got %v after %d calls, want context.Canceled after 1", err, *calls)
+	}
+}
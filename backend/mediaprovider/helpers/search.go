@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
@@ -65,3 +66,33 @@ func RankSearchResults(results []*mediaprovider.SearchResult, fullQuery string,
 		return a.Type < b.Type
 	})
 }
+
+// max number of search results examined per type by SearchWithinArtist
+const searchWithinArtistMaxResults = 500
+
+// SearchWithinArtist runs a library search for the query and returns
+// only the albums and tracks credited to the given artist, whether as
+// primary artist or, for tracks, as a contributor (composer).
+// Empty (non-nil) slices are returned if nothing matches.
+func SearchWithinArtist(mp mediaprovider.MediaProvider, artistID, query string) ([]*mediaprovider.Album, []*mediaprovider.Track, error) {
+	albums := make([]*mediaprovider.Album, 0)
+	tracks := make([]*mediaprovider.Track, 0)
+	if strings.TrimSpace(query) == "" {
+		return albums, tracks, nil
+	}
+
+	albumIter := mp.SearchAlbums(query, mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{}))
+	for i, al := 0, albumIter.Next(); al != nil && i < searchWithinArtistMaxResults; i, al = i+1, albumIter.Next() {
+		if slices.Contains(al.ArtistIDs, artistID) {
+			albums = append(albums, al)
+		}
+	}
+
+	trackIter := mp.IterateTracks(query)
+	for i, tr := 0, trackIter.Next(); tr != nil && i < searchWithinArtistMaxResults; i, tr = i+1, trackIter.Next() {
+		if slices.Contains(tr.ArtistIDs, artistID) || slices.Contains(tr.ComposerIDs, artistID) {
+			tracks = append(tracks, tr)
+		}
+	}
+	return albums, tracks, nil
+}
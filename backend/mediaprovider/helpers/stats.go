@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+const (
+	listeningStatsTopCount = 10
+	// upper bound on recently played albums examined for stats
+	listeningStatsMaxAlbums = 500
+)
+
+// GetListeningStats returns aggregate listening statistics for the range [from, to].
+//
+// Servers don't expose a per-play history, so the stats are an approximation:
+// the albums last played within the range are found from the recently played
+// list, and every track on them last played within the range contributes its
+// lifetime play count. Plays from before the range are thus counted for
+// tracks that were also played within it, and plays within the range of
+// tracks that have since been played again are missed.
+func GetListeningStats(mp mediaprovider.MediaProvider, from, to time.Time) (*mediaprovider.ListeningStats, error) {
+	if to.Before(from) {
+		return nil, errors.New("invalid date range")
+	}
+	iter := mp.IterateAlbums(mediaprovider.AlbumSortRecentlyPlayed,
+		mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{}))
+	if iter == nil {
+		return nil, errors.New("recently played sort order not supported")
+	}
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	stats := &mediaprovider.ListeningStats{}
+	albumPlays := make(map[string]int)
+	artistPlays := make(map[string]*mediaprovider.ArtistPlayCount)
+	var albums []*mediaprovider.Album
+	var tracks []*mediaprovider.Track
+	for i := 0; i < listeningStatsMaxAlbums; i++ {
+		al := iter.Next()
+		if al == nil {
+			break
+		}
+		// albums are sorted most recently played first. Providers that
+		// don't report album last played times leave it zero, in which
+		// case the track play times below decide what is in range.
+		if !al.LastPlayed.IsZero() {
+			if al.LastPlayed.Before(from) {
+				break
+			}
+			if al.LastPlayed.After(to) {
+				continue
+			}
+		}
+		album, err := mp.GetAlbum(al.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading album tracks: %v", err.Error())
+		}
+		for _, tr := range album.Tracks {
+			if tr.PlayCount == 0 || !inRange(tr.LastPlayed) {
+				continue
+			}
+			tracks = append(tracks, tr)
+			stats.TotalPlays += tr.PlayCount
+			stats.TotalTime += time.Duration(tr.PlayCount*tr.Duration) * time.Second
+			albumPlays[al.ID] += tr.PlayCount
+			for j, id := range tr.ArtistIDs {
+				a, ok := artistPlays[id]
+				if !ok {
+					a = &mediaprovider.ArtistPlayCount{ArtistID: id}
+					if j < len(tr.ArtistNames) {
+						a.ArtistName = tr.ArtistNames[j]
+					}
+					artistPlays[id] = a
+				}
+				a.PlayCount += tr.PlayCount
+			}
+		}
+		if albumPlays[al.ID] > 0 {
+			albums = append(albums, al)
+		}
+	}
+
+	slices.SortStableFunc(tracks, func(a, b *mediaprovider.Track) int {
+		return cmp.Compare(b.PlayCount, a.PlayCount)
+	})
+	stats.TopTracks = tracks[:min(len(tracks), listeningStatsTopCount)]
+
+	slices.SortStableFunc(albums, func(a, b *mediaprovider.Album) int {
+		return cmp.Compare(albumPlays[b.ID], albumPlays[a.ID])
+	})
+	stats.TopAlbums = albums[:min(len(albums), listeningStatsTopCount)]
+
+	artists := make([]mediaprovider.ArtistPlayCount, 0, len(artistPlays))
+	for _, a := range artistPlays {
+		artists = append(artists, *a)
+	}
+	slices.SortFunc(artists, func(a, b mediaprovider.ArtistPlayCount) int {
+		if c := cmp.Compare(b.PlayCount, a.PlayCount); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ArtistName, b.ArtistName)
+	})
+	stats.TopArtists = artists[:min(len(artists), listeningStatsTopCount)]
+	return stats, nil
+}
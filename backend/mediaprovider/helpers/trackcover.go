@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"image"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// GetTrackCoverArt returns the cover art for the track, falling back to
+// the cover of its album and then of its (first) artist if the track has
+// no cover art ID or loading it fails. Returns mediaprovider.ErrNoCover
+// only if none of them could be loaded.
+func GetTrackCoverArt(mp mediaprovider.MediaProvider, track *mediaprovider.Track, size int) (image.Image, error) {
+	if track == nil {
+		return nil, mediaprovider.ErrNoCover
+	}
+	tried := make(map[string]bool)
+	tryCover := func(id string) image.Image {
+		if id == "" || tried[id] {
+			return nil
+		}
+		tried[id] = true
+		if img, err := mp.GetCoverArt(id, size); err == nil && img != nil {
+			return img
+		}
+		return nil
+	}
+
+	if img := tryCover(track.CoverArtID); img != nil {
+		return img, nil
+	}
+	if track.AlbumID != "" {
+		if al, err := mp.GetAlbum(track.AlbumID); err == nil {
+			if img := tryCover(al.CoverArtID); img != nil {
+				return img, nil
+			}
+		}
+	}
+	if len(track.ArtistIDs) > 0 && track.ArtistIDs[0] != "" {
+		if ar, err := mp.GetArtist(track.ArtistIDs[0]); err == nil {
+			if img := tryCover(ar.CoverArtID); img != nil {
+				return img, nil
+			}
+		}
+	}
+	return nil, mediaprovider.ErrNoCover
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// GetTracks looks up the tracks with the given IDs, returning them in the
+// same order. Tracks that could not be loaded are nil entries rather than
+// failing the whole lookup; an error is returned only if none could be
+// loaded. Uses the provider's batch lookup if supported, and otherwise
+// concurrent GetTrack calls.
+func GetTracks(mp mediaprovider.MediaProvider, trackIDs []string) ([]*mediaprovider.Track, error) {
+	if b, ok := mp.(mediaprovider.SupportsBatchTrackLookup); ok {
+		tracks, err := b.GetTracks(trackIDs)
+		if err != nil && !anyNonNil(tracks) {
+			return nil, err
+		}
+		return tracks, nil
+	}
+
+	tracks := make([]*mediaprovider.Track, len(trackIDs))
+	errs := make([]error, len(trackIDs))
+	RunConcurrently(context.Background(), len(trackIDs), BatchConcurrency(mp), func(i int) {
+		tracks[i], errs[i] = mp.GetTrack(trackIDs[i])
+	})
+	if !anyNonNil(tracks) {
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tracks, nil
+}
+
+func anyNonNil[T any](s []*T) bool {
+	for _, t := range s {
+		if t != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// RefreshTrackState re-fetches the track from the server and updates its
+// user-mutable state - rating, favorite, play count and last played -
+// in place, leaving the rest of its metadata untouched. This allows
+// UI bound to the track to pick up e.g. a rating change without
+// replacing the object.
+func RefreshTrackState(mp mediaprovider.MediaProvider, track *mediaprovider.Track) error {
+	fresh, err := mp.GetTrack(track.ID)
+	if err != nil {
+		return fmt.Errorf("error refreshing track state: %v", err.Error())
+	}
+	applyTrackState(track, fresh)
+	return nil
+}
+
+func applyTrackState(track, fresh *mediaprovider.Track) {
+	track.Rating = fresh.Rating
+	track.Favorite = fresh.Favorite
+	track.PlayCount = fresh.PlayCount
+	track.LastPlayed = fresh.LastPlayed
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+func TestApplyTrackState(t *testing.T) {
+	track := &mediaprovider.Track{
+		ID:          "1",
+		Title:       "So What",
+		Album:       "Kind of Blue",
+		ArtistNames: []string{"Miles Davis"},
+		Rating:      3,
+		PlayCount:   7,
+	}
+	played := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fresh := &mediaprovider.Track{
+		ID:          "1",
+		Title:       "So What (Remastered)",
+		Album:       "Kind of Blue (Legacy Edition)",
+		ArtistNames: []string{"Miles Davis", "John Coltrane"},
+		Rating:      5,
+		Favorite:    true,
+		PlayCount:   8,
+		LastPlayed:  played,
+	}
+
+	want := *track
+	want.Rating = 5
+	want.Favorite = true
+	want.PlayCount = 8
+	want.LastPlayed = played
+
+	applyTrackState(track, fresh)
+	if !reflect.DeepEqual(*track, want) {
+		t.Errorf("got %+v, want %+v", *track, want)
+	}
+}
@@ -1,10 +1,12 @@
 package jellyfin
 
 import (
+	"fmt"
 	"image"
 	"io"
 	"math"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -167,6 +169,10 @@ func (j *jellyfinMediaProvider) GetArtistInfo(artistID string) (*mediaprovider.A
 func (j *jellyfinMediaProvider) GetTrack(trackID string) (*mediaprovider.Track, error) {
 	tr, err := j.client.GetSong(trackID)
 	if err != nil {
+		// go-jellyfin reports HTTP statuses only in the error message
+		if strings.Contains(err.Error(), "code: 404") {
+			return nil, fmt.Errorf("%w: %s", mediaprovider.ErrNotFound, err.Error())
+		}
 		return nil, err
 	}
 	return toTrack(tr), nil
@@ -463,6 +469,9 @@ func fillAlbum(a *jellyfin.Album, album *mediaprovider.Album) {
 	album.Genres = a.Genres
 	album.Favorite = a.UserData.IsFavorite
 	album.ReleaseTypes = mediaprovider.ReleaseTypeAlbum
+	if t, err := time.Parse(time.RFC3339Nano, a.DateCreated); err == nil {
+		album.Created = t
+	}
 }
 
 func (j *jellyfinMediaProvider) toPlaylist(p *jellyfin.Playlist) *mediaprovider.Playlist {
@@ -481,6 +490,12 @@ func (j *jellyfinMediaProvider) fillPlaylist(p *jellyfin.Playlist, pl *mediaprov
 	// Jellyfin does not have public playlists
 	pl.Owner = j.client.LoggedInUser()
 	pl.Public = false
+	for _, date := range []string{p.DateLastMediaAdded, p.DateCreated} {
+		if t, err := time.Parse(time.RFC3339Nano, date); err == nil {
+			pl.ChangedSince = t
+			break
+		}
+	}
 }
 
 func (j *jellyfinMediaProvider) GetSongRadio(trackID string, count int) ([]*mediaprovider.Track, error) {
@@ -1,10 +1,14 @@
 package mediaprovider
 
 import (
+	"context"
+	"errors"
 	"image"
 	"io"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/deluan/sanitize"
 )
@@ -20,14 +24,45 @@ const (
 	AlbumSortArtistAZ         string = "Artist (A-Z)"
 	AlbumSortYearAscending    string = "Year (ascending)"
 	AlbumSortYearDescending   string = "Year (descending)"
+	AlbumSortHighestRated     string = "Highest Rated"
+	AlbumSortFavorites        string = "Favorites"
 
 	// set of all supported artist sorts across all media providers
 	// these strings may be translated
-	ArtistSortAlbumCount string = "Album Count"
-	ArtistSortNameAZ     string = "Name (A-Z)"
-	ArtistSortRandom     string = "Random"
+	ArtistSortAlbumCount       string = "Album Count"
+	ArtistSortNameAZ           string = "Name (A-Z)"
+	ArtistSortRandom           string = "Random"
+	ArtistSortFrequentlyPlayed string = "Frequently Played"
+	ArtistSortRecentlyPlayed   string = "Recently Played"
 )
 
+var (
+	ErrNoMatchingAlbum = errors.New("no album matches the filter")
+	ErrNotAuthorized   = errors.New("user is not authorized to perform this operation")
+	ErrNotSupported    = errors.New("operation not supported by the server")
+	ErrNotFound        = errors.New("not found")
+	ErrNoCover         = errors.New("no cover art available")
+	ErrAuthFailed      = errors.New("authentication with the server failed")
+)
+
+// IsAuthError reports whether err is caused by the server rejecting the
+// user's credentials, meaning the user must log in again, as opposed to
+// a transient network or server error.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuthFailed)
+}
+
+// ValidationError is returned when the parameters of a request
+// are rejected before it is sent to the server.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (v *ValidationError) Error() string {
+	return v.Field + ": " + v.Reason
+}
+
 type MediaIterator[M any] interface {
 	Next() *M
 }
@@ -53,18 +88,39 @@ type AlbumFilterOptions struct {
 
 	ExcludeFavorited   bool // mut. exc. with ExcludeUnfavorited
 	ExcludeUnfavorited bool // mut. exc. with ExcludeFavorited
+
+	RequireCoverArt   bool // mut. exc. with RequireNoCoverArt
+	RequireNoCoverArt bool // mut. exc. with RequireCoverArt
+
+	// Depends on the server providing explicit metadata
+	// (see SupportsExplicitMetadata); albums without it
+	// are treated as non-explicit
+	ExcludeExplicit bool
+
+	ArtistIDs []string // len(0) == unset/match any
+
+	// Matches albums having any of the given release types;
+	// albums without release type info count as ReleaseTypeAlbum
+	ReleaseTypes ReleaseTypes // 0 == unset/match any
 }
 
 // Clone returns a deep copy of the filter options
 func (o AlbumFilterOptions) Clone() AlbumFilterOptions {
 	genres := make([]string, len(o.Genres))
 	copy(genres, o.Genres)
+	artistIDs := make([]string, len(o.ArtistIDs))
+	copy(artistIDs, o.ArtistIDs)
 	return AlbumFilterOptions{
 		MinYear:            o.MinYear,
 		MaxYear:            o.MaxYear,
 		Genres:             genres,
 		ExcludeFavorited:   o.ExcludeFavorited,
 		ExcludeUnfavorited: o.ExcludeUnfavorited,
+		RequireCoverArt:    o.RequireCoverArt,
+		RequireNoCoverArt:  o.RequireNoCoverArt,
+		ExcludeExplicit:    o.ExcludeExplicit,
+		ArtistIDs:          artistIDs,
+		ReleaseTypes:       o.ReleaseTypes,
 	}
 }
 
@@ -93,7 +149,11 @@ func (a albumFilter) Clone() AlbumFilter {
 func (a albumFilter) IsNil() bool {
 	return a.options.MinYear == 0 && a.options.MaxYear == 0 &&
 		len(a.options.Genres) == 0 &&
-		!a.options.ExcludeFavorited && !a.options.ExcludeUnfavorited
+		!a.options.ExcludeFavorited && !a.options.ExcludeUnfavorited &&
+		!a.options.RequireCoverArt && !a.options.RequireNoCoverArt &&
+		!a.options.ExcludeExplicit &&
+		len(a.options.ArtistIDs) == 0 &&
+		a.options.ReleaseTypes == 0
 }
 
 func (f albumFilter) Matches(album *Album) bool {
@@ -106,25 +166,168 @@ func (f albumFilter) Matches(album *Album) bool {
 	if f.options.ExcludeUnfavorited && !album.Favorite {
 		return false
 	}
+	if f.options.RequireCoverArt && album.CoverArtID == "" {
+		return false
+	}
+	if f.options.RequireNoCoverArt && album.CoverArtID != "" {
+		return false
+	}
+	if f.options.ExcludeExplicit && album.Explicit {
+		return false
+	}
 	if y := album.YearOrZero(); y < f.options.MinYear || (f.options.MaxYear > 0 && y > f.options.MaxYear) {
 		return false
 	}
+	if f.options.ReleaseTypes != 0 {
+		types := album.ReleaseTypes
+		if types == 0 {
+			types = ReleaseTypeAlbum
+		}
+		if types&f.options.ReleaseTypes == 0 {
+			return false
+		}
+	}
+	if len(f.options.ArtistIDs) > 0 && !slices.ContainsFunc(album.ArtistIDs, func(id string) bool {
+		return slices.Contains(f.options.ArtistIDs, id)
+	}) {
+		return false
+	}
 	if len(f.options.Genres) == 0 {
 		return true
 	}
 	return genresMatch(f.options.Genres, album.Genres)
 }
 
+type TrackFilter = MediaFilter[Track, TrackFilterOptions]
+
+type TrackFilterOptions struct {
+	MinYear int
+	MaxYear int      // 0 == unset/match any
+	Genres  []string // len(0) == unset/match any
+
+	MinRating int // 0 == unset/match any
+	MaxRating int // 0 == unset/match any
+
+	MinBitRate int // kbps, 0 == unset/match any
+	MaxBitRate int // kbps, 0 == unset/match any
+
+	// Case-insensitive substring match against any of the track's artists
+	ArtistName string
+
+	ExcludeFavorited   bool // mut. exc. with ExcludeUnfavorited
+	ExcludeUnfavorited bool // mut. exc. with ExcludeFavorited
+
+	// Depends on the server providing explicit metadata
+	// (see SupportsExplicitMetadata); tracks without it
+	// are treated as non-explicit
+	ExcludeExplicit bool // mut. exc. with RequireExplicit
+	RequireExplicit bool // mut. exc. with ExcludeExplicit
+}
+
+// Clone returns a deep copy of the filter options
+func (o TrackFilterOptions) Clone() TrackFilterOptions {
+	genres := make([]string, len(o.Genres))
+	copy(genres, o.Genres)
+	return TrackFilterOptions{
+		MinYear:            o.MinYear,
+		MaxYear:            o.MaxYear,
+		Genres:             genres,
+		MinRating:          o.MinRating,
+		MaxRating:          o.MaxRating,
+		MinBitRate:         o.MinBitRate,
+		MaxBitRate:         o.MaxBitRate,
+		ArtistName:         o.ArtistName,
+		ExcludeFavorited:   o.ExcludeFavorited,
+		ExcludeUnfavorited: o.ExcludeUnfavorited,
+		ExcludeExplicit:    o.ExcludeExplicit,
+		RequireExplicit:    o.RequireExplicit,
+	}
+}
+
+type trackFilter struct {
+	options TrackFilterOptions
+}
+
+func NewTrackFilter(options TrackFilterOptions) *trackFilter {
+	return &trackFilter{options}
+}
+
+func (t trackFilter) Options() TrackFilterOptions {
+	return t.options
+}
+
+func (t *trackFilter) SetOptions(options TrackFilterOptions) {
+	t.options = options
+}
+
+// Clone returns a deep copy of the filter
+func (t trackFilter) Clone() TrackFilter {
+	return NewTrackFilter(t.options.Clone())
+}
+
+// Returns true if the filter is the nil filter - i.e. matches everything
+func (t trackFilter) IsNil() bool {
+	return t.options.MinYear == 0 && t.options.MaxYear == 0 &&
+		len(t.options.Genres) == 0 &&
+		t.options.MinRating == 0 && t.options.MaxRating == 0 &&
+		t.options.MinBitRate == 0 && t.options.MaxBitRate == 0 &&
+		t.options.ArtistName == "" &&
+		!t.options.ExcludeFavorited && !t.options.ExcludeUnfavorited &&
+		!t.options.ExcludeExplicit && !t.options.RequireExplicit
+}
+
+func (f trackFilter) Matches(track *Track) bool {
+	if track == nil {
+		return false
+	}
+	if f.options.ExcludeFavorited && track.Favorite {
+		return false
+	}
+	if f.options.ExcludeUnfavorited && !track.Favorite {
+		return false
+	}
+	if f.options.ExcludeExplicit && track.Explicit {
+		return false
+	}
+	if f.options.RequireExplicit && !track.Explicit {
+		return false
+	}
+	if track.Year < f.options.MinYear || (f.options.MaxYear > 0 && track.Year > f.options.MaxYear) {
+		return false
+	}
+	if track.Rating < f.options.MinRating || (f.options.MaxRating > 0 && track.Rating > f.options.MaxRating) {
+		return false
+	}
+	if track.BitRate < f.options.MinBitRate || (f.options.MaxBitRate > 0 && track.BitRate > f.options.MaxBitRate) {
+		return false
+	}
+	if f.options.ArtistName != "" && !artistNameMatches(f.options.ArtistName, track.ArtistNames) {
+		return false
+	}
+	if len(f.options.Genres) == 0 {
+		return true
+	}
+	return genresMatch(f.options.Genres, track.Genres)
+}
+
 type ArtistFilter = MediaFilter[Artist, ArtistFilterOptions]
 
 type ArtistFilterOptions struct {
 	SearchQuery string
+
+	ExcludeFavorited   bool // mut. exc. with ExcludeUnfavorited
+	ExcludeUnfavorited bool // mut. exc. with ExcludeFavorited
+
+	MinAlbumCount int // 0 == unset/match any
 }
 
 // Clone returns a deep copy of the filter options
 func (o ArtistFilterOptions) Clone() ArtistFilterOptions {
 	return ArtistFilterOptions{
-		SearchQuery: o.SearchQuery,
+		SearchQuery:        o.SearchQuery,
+		ExcludeFavorited:   o.ExcludeFavorited,
+		ExcludeUnfavorited: o.ExcludeUnfavorited,
+		MinAlbumCount:      o.MinAlbumCount,
 	}
 }
 
@@ -151,13 +354,24 @@ func (a artistFilter) Clone() ArtistFilter {
 
 // Returns true if the filter is the nil filter - i.e. matches everything
 func (a artistFilter) IsNil() bool {
-	return a.options.SearchQuery == ""
+	return a.options.SearchQuery == "" &&
+		!a.options.ExcludeFavorited && !a.options.ExcludeUnfavorited &&
+		a.options.MinAlbumCount == 0
 }
 
 func (f artistFilter) Matches(artist *Artist) bool {
 	if artist == nil {
 		return false
 	}
+	if f.options.ExcludeFavorited && artist.Favorite {
+		return false
+	}
+	if f.options.ExcludeUnfavorited && !artist.Favorite {
+		return false
+	}
+	if artist.AlbumCount < f.options.MinAlbumCount {
+		return false
+	}
 	if f.options.SearchQuery != "" && !strings.Contains(
 		sanitize.Accents(strings.ToLower(artist.Name)),
 		sanitize.Accents(strings.ToLower(f.options.SearchQuery)),
@@ -279,20 +493,336 @@ type SupportsSharing interface {
 	CanShareArtists() bool
 }
 
+// Implemented by providers that can list and manage the user's share links.
+type SupportsShareManagement interface {
+	// True if the server allows the logged in user to create shares.
+	CanManageShares() bool
+	GetShares() ([]*Share, error)
+	// Creates a share of the given album, track or playlist IDs.
+	// A zero expires creates a share that doesn't expire.
+	CreateShare(ids []string, description string, expires time.Time) (*Share, error)
+	UpdateShare(id string, description string, expires time.Time) error
+	DeleteShare(id string) error
+}
+
 type CanSavePlayQueue interface {
 	SavePlayQueue(trackIDs []string, currentTrackPos int, timeSeconds int) error
+
+	// Returns nil, nil if the server has no saved play queue.
 	GetPlayQueue() (*SavedPlayQueue, error)
 }
 
 type LyricsProvider interface {
+	// Returns the track's lyrics, time-synced if the server provides them,
+	// or nil if the server has none.
 	GetLyrics(track *Track) (*Lyrics, error)
 }
 
+// Implemented by providers that can restrict genre and random
+// track fetches to a single music folder (library).
+// An empty musicFolderID means all folders.
+type SupportsMusicFolderScoping interface {
+	GetRandomTracksInMusicFolder(genre string, count int, musicFolderID string) ([]*Track, error)
+	GetGenreTracks(genre string, count int, musicFolderID string) ([]*Track, error)
+}
+
+// Implemented by providers that can restrict browsing to one
+// of the server's music folders (libraries).
+type SupportsActiveMusicFolder interface {
+	GetMusicFolders() ([]*MusicFolder, error)
+
+	// Restricts album and artist iteration and random tracks to the
+	// music folder with the given ID. "" means all folders (the default).
+	SetActiveMusicFolder(id string)
+}
+
+// Implemented by providers that support browsing the library by its
+// folder structure, as an alternative to browsing by tags.
+type SupportsDirectoryBrowsing interface {
+	GetIndexes() ([]*DirectoryIndex, error)
+
+	GetMusicDirectory(id string) (*Directory, error)
+}
+
+// Implemented by providers that can report what
+// all users of the server are currently playing.
+type SupportsNowPlaying interface {
+	// Returns an empty slice if nothing is playing.
+	GetNowPlaying() ([]*NowPlayingEntry, error)
+}
+
+type SupportsAlbumCount interface {
+	// Returns the number of albums IterateAlbums will yield for the
+	// filter, e.g. to size a grid, or -1 if the server can't provide
+	// the total for the filter. The count may be approximate.
+	CountAlbums(filter AlbumFilter) (int, error)
+}
+
+type SupportsRandomAlbum interface {
+	// Returns a random album matching the filter, with its tracks.
+	// Returns ErrNoMatchingAlbum if none is found after a bounded number of attempts.
+	GetRandomAlbum(filter AlbumFilter) (*AlbumWithTracks, error)
+}
+
+type SupportsStreamOptions interface {
+	GetStreamURLWithOptions(trackID string, opts StreamOptions) (string, error)
+}
+
+type SupportsStreamURLBoth interface {
+	// Returns both the raw (original file) and default transcoded
+	// stream URLs for the track, sharing the same authentication.
+	GetStreamURLBoth(trackID string) (rawURL, transcodedURL string, err error)
+}
+
+type SupportsCoverArtPrefetch interface {
+	// Prefetches the cover art for the given IDs in the background
+	// through the prefetch cover callback, with a bounded number of
+	// fetches in flight. Duplicate and empty IDs are skipped.
+	PrefetchCoverArt(ids []string)
+}
+
+type SupportsCoverArtBestFit interface {
+	// Fetches the cover art at the smallest configured preset size at
+	// least as large as targetPx (or the largest preset if none is),
+	// so that a bounded set of sizes is requested and cached.
+	GetCoverArtBestFit(coverArtID string, targetPx int) (image.Image, error)
+}
+
+type SupportsCoverArtBytes interface {
+	// Fetches the raw, undecoded cover art image data along with
+	// its MIME content type (e.g. "image/jpeg"), so callers can
+	// cache or forward the original bytes without re-encoding.
+	GetCoverArtBytes(coverArtID string, size int) ([]byte, string, error)
+}
+
+type SupportsAlbumStreamURLs interface {
+	// Returns the album along with the stream URLs of its tracks, in track order.
+	GetAlbumWithStreamURLs(ctx context.Context, albumID string, forceRaw bool) (*AlbumWithTracks, []string, error)
+}
+
+type SupportsCreatePlaylistWithID interface {
+	// Like CreatePlaylist, but returns the ID of the new playlist,
+	// or the empty string if the server doesn't report it.
+	CreatePlaylistWithID(name string, trackIDs []string) (string, error)
+}
+
+type SupportsPlaylistDownload interface {
+	// Downloads the playlist's tracks one at a time, sending each on the
+	// returned channel, which is closed when done or when ctx is canceled.
+	// Each Reader must be closed by the caller; the next track's download
+	// doesn't start until it is.
+	// If transcoded is true, the stream is transcoded using the given max
+	// bit rate and format (0 and "" respectively for server defaults).
+	// progress, if non-nil, is invoked after each track's Reader is closed
+	// (or, for a track that failed to download, after it is sent).
+	DownloadPlaylist(ctx context.Context, playlistID string, transcoded bool, maxBitRate int, format string, progress func(done, total int)) (<-chan DownloadedTrack, error)
+}
+
+type SupportsFullRescan interface {
+	// Starts a full library rescan, re-reading the tags of all files,
+	// rather than the quick incremental scan of RescanLibrary.
+	FullRescanLibrary() error
+}
+
+type SupportsScanStatus interface {
+	GetScanStatus() (*ScanStatus, error)
+
+	// Starts a library rescan as RescanLibrary does,
+	// returning the scan status reported when it started.
+	RescanLibraryWithStatus() (*ScanStatus, error)
+}
+
+type SupportsUserManagement interface {
+	// Returns the currently logged in user.
+	GetCurrentUser() (*User, error)
+
+	// Returns all users on the server. Requires the admin role,
+	// otherwise ErrNotAuthorized is returned.
+	GetUsers() ([]*User, error)
+
+	// Creates a new user. Requires the admin role. Returns a
+	// *ValidationError if a required parameter is missing.
+	CreateUser(params CreateUserParams) error
+
+	// Deletes the user. Requires the admin role.
+	DeleteUser(username string) error
+}
+
+type SupportsTrackPriming interface {
+	// Warms up the server's transcoder/cache and the connection for a track
+	// that is about to be played by requesting its first few KB in the
+	// background. Network errors are ignored since this is an optimization.
+	PrimeNextTrack(trackID string) error
+}
+
+type SupportsLibrarySections interface {
+	// Returns the server's top-level library folders, with
+	// the type of media each contains inferred from a sample of its tracks.
+	GetLibrarySections() ([]*LibrarySection, error)
+}
+
+type SupportsPodcasts interface {
+	GetPodcastChannels() ([]*PodcastChannel, error)
+
+	// Returns all the channel's episodes, including ones not (yet)
+	// downloaded by the server, which have an empty StreamID.
+	GetPodcastEpisodes(channelID string) ([]*PodcastEpisode, error)
+
+	// Requests the server to download the episode. The download runs
+	// server-side; its progress is reflected in the episode's Status.
+	// Returns ErrNotAuthorized if the user lacks podcast rights.
+	DownloadPodcastEpisode(episodeID string) error
+
+	// Requests the server to check all channels for new episodes.
+	// Returns ErrNotAuthorized if the user lacks podcast rights.
+	RefreshPodcasts() error
+}
+
+// Implemented by providers that can save per-track resume positions,
+// e.g. for audiobooks. Bookmarks are independent of the play queue;
+// each track has at most one, which CreateBookmark replaces.
+type SupportsBookmarks interface {
+	GetBookmarks() ([]*Bookmark, error)
+	CreateBookmark(trackID string, positionSeconds int, comment string) error
+	DeleteBookmark(trackID string) error
+}
+
+type SupportsPlayHistory interface {
+	// Returns up to limit entries of the user's play history, most recent first.
+	// Returns ErrNotSupported if the server doesn't expose its play history.
+	GetPlayHistory(limit int) ([]*PlayHistoryEntry, error)
+}
+
+// Implemented by providers that cache genre and playlist listings.
+// The default cache duration is provider-specific;
+// a TTL of 0 disables caching.
+type SupportsCacheTTL interface {
+	SetCacheTTL(d time.Duration)
+}
+
+// Implemented by providers that cache server state, for callers
+// that modify that state other than through the provider.
+type SupportsCacheInvalidation interface {
+	// Drops all cached data so that it is re-fetched on next request.
+	InvalidateCaches()
+}
+
+type SupportsDiverseSimilarTracks interface {
+	// Returns up to count tracks similar to the given track, excluding
+	// tracks from the same album and preferring other artists.
+	// May return fewer than count if few remain after filtering.
+	GetSimilarTracksDiverse(trackID string, count int) ([]*Track, error)
+}
+
+// Implemented by providers that can cheaply check whether an item
+// exists and is accessible, e.g. to validate deep links. The checks
+// return false with a nil error if the item is not found, and an
+// error only if the check itself failed.
+type SupportsExistenceCheck interface {
+	AlbumExists(albumID string) (bool, error)
+	ArtistExists(artistID string) (bool, error)
+	TrackExists(trackID string) (bool, error)
+}
+
+type SupportsGenresByListening interface {
+	// Returns the genres ordered by the user's listening, most played
+	// first, falling back to album count where plays aren't known.
+	GetGenresByListening() ([]*Genre, error)
+}
+
+type SupportsStreamURLRewriting interface {
+	// Sets a function applied to every stream URL the provider returns,
+	// e.g. to map an internal server host to one reachable through a
+	// reverse proxy or CDN. The rewriter receives the complete URL,
+	// including any auth parameters, and its result is returned as is.
+	// A nil result leaves the URL unchanged; a nil rewriter removes it.
+	SetStreamURLRewriter(rewriter func(u *url.URL) *url.URL)
+}
+
+// Implemented by providers that can record the actual time playback
+// of a track began, e.g. when playback was delayed by buffering,
+// rather than the time they are notified of it. Servers forwarding
+// scrobbles to Last.fm deduplicate them by this timestamp.
+type SupportsPlaybackStartTime interface {
+	TrackBeganPlaybackAt(trackID string, startTime time.Time) error
+
+	TrackEndedPlaybackAt(trackID string, positionSecs int, submission bool, startTime time.Time) error
+}
+
+// Implemented by providers that can look up many tracks more
+// efficiently than by individual GetTrack calls.
+type SupportsBatchTrackLookup interface {
+	// Returns the tracks in the order of trackIDs, with nil entries
+	// for IDs that were not found. The error reports lookups that
+	// failed for other reasons, whose entries are also nil.
+	GetTracks(trackIDs []string) ([]*Track, error)
+}
+
+// Implemented by providers that support the server's chat.
+type SupportsChat interface {
+	// Returns the messages posted after since,
+	// or all messages if since is the zero time.
+	GetChatMessages(since time.Time) ([]*ChatMessage, error)
+
+	SendChatMessage(text string) error
+}
+
+type SupportsPagedSearch interface {
+	// Returns count results of the given type (artist, album, or track)
+	// matching the query, starting at offset, in the server's order.
+	// Returns ErrNotSupported for other content types.
+	SearchAllPaged(searchQuery string, kind ContentType, offset, count int) ([]*SearchResult, error)
+}
+
+// Canceling ctx only stops the caller from waiting: requests already
+// sent to the server are not aborted, as not all server clients support
+// it. They run to completion in the background and their results are
+// discarded, so cancellation does not reduce load on the server.
+type SupportsContext interface {
+	// Like IterateAlbums, but once ctx is canceled, pending fetches
+	// are abandoned and the iterator returns nil.
+	IterateAlbumsContext(ctx context.Context, sortOrder string, filter AlbumFilter) AlbumIterator
+
+	// Like SearchAll, but returns ctx.Err() as soon as ctx is canceled.
+	SearchAllContext(ctx context.Context, searchQuery string, maxResults int) ([]*SearchResult, error)
+}
+
+type SupportsServerMessage interface {
+	// Returns the latest announcement posted to the server, or the empty
+	// string if there is none. Providers without a dedicated announcement
+	// field may only report messages from a configured sender.
+	GetServerMessage() (string, error)
+}
+
+// Implemented by providers that populate Album.Explicit and Track.Explicit.
+// On other providers, calls that filter on explicit content (ExcludeExplicit
+// or RequireExplicit) return ErrNotSupported if they can return an error.
+type SupportsExplicitMetadata interface {
+	// Returns true if the server reports whether albums and tracks are explicit.
+	ReportsExplicit() bool
+}
+
+type SupportsPlaceholderCoverArt interface {
+	// Like GetCoverArt, but if the item has no cover art (coverArtID is
+	// empty), may instead return a generated placeholder. seed identifies
+	// the item - its name, or its ID if it has none - so that each item
+	// gets its own consistent placeholder.
+	GetCoverArtOrPlaceholder(coverArtID, seed string, size int) (image.Image, error)
+}
+
 type RadioProvider interface {
 	GetRadioStation(id string) (*RadioStation, error)
 	GetRadioStations() ([]*RadioStation, error)
 }
 
+// Implemented by providers that can manage the server's
+// internet radio stations, in addition to listing them.
+type SupportsRadioManagement interface {
+	CreateRadioStation(name, streamURL, homepageURL string) error
+	UpdateRadioStation(id, name, streamURL, homepageURL string) error
+	DeleteRadioStation(id string) error
+}
+
 type JukeboxProvider interface {
 	JukeboxStart() error
 	JukeboxStop() error
@@ -327,3 +857,13 @@ func genresMatch(filterGenres, albumGenres []string) bool {
 	}
 	return false
 }
+
+func artistNameMatches(filterName string, artistNames []string) bool {
+	filterName = strings.ToLower(filterName)
+	for _, a := range artistNames {
+		if strings.Contains(strings.ToLower(a), filterName) {
+			return true
+		}
+	}
+	return false
+}
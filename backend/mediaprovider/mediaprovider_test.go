@@ -0,0 +1,117 @@
+package mediaprovider
+
+import "testing"
+
+func TestAlbumFilter_CoverArt(t *testing.T) {
+	withCover := &Album{ID: "1", CoverArtID: "al-1"}
+	withoutCover := &Album{ID: "2"}
+
+	f := NewAlbumFilter(AlbumFilterOptions{RequireCoverArt: true})
+	if f.IsNil() {
+		t.Error("RequireCoverArt filter should not be nil")
+	}
+	if !f.Matches(withCover) {
+		t.Error("RequireCoverArt filter should match album with cover art")
+	}
+	if f.Matches(withoutCover) {
+		t.Error("RequireCoverArt filter should not match album without cover art")
+	}
+
+	f = NewAlbumFilter(AlbumFilterOptions{RequireNoCoverArt: true})
+	if f.IsNil() {
+		t.Error("RequireNoCoverArt filter should not be nil")
+	}
+	if f.Matches(withCover) {
+		t.Error("RequireNoCoverArt filter should not match album with cover art")
+	}
+	if !f.Matches(withoutCover) {
+		t.Error("RequireNoCoverArt filter should match album without cover art")
+	}
+
+	if c := f.Clone(); !c.Options().RequireNoCoverArt {
+		t.Error("Clone should preserve RequireNoCoverArt")
+	}
+}
+
+func TestTrackFilter_BitRate(t *testing.T) {
+	f := NewTrackFilter(TrackFilterOptions{MinBitRate: 192, MaxBitRate: 320})
+	if f.IsNil() {
+		t.Error("bit rate filter should not be nil")
+	}
+	for _, tc := range []struct {
+		bitRate int
+		want    bool
+	}{
+		{128, false},
+		{192, true},
+		{320, true},
+		{1411, false},
+	} {
+		if got := f.Matches(&Track{BitRate: tc.bitRate}); got != tc.want {
+			t.Errorf("Matches(BitRate %d) = %v, want %v", tc.bitRate, got, tc.want)
+		}
+	}
+
+	if c := f.Clone(); c.Options().MaxBitRate != 320 {
+		t.Error("Clone should preserve MaxBitRate")
+	}
+}
+
+func TestArtistFilter(t *testing.T) {
+	fav := &Artist{ID: "1", Favorite: true, AlbumCount: 3}
+	unfav := &Artist{ID: "2", AlbumCount: 1}
+
+	f := NewArtistFilter(ArtistFilterOptions{ExcludeUnfavorited: true})
+	if f.IsNil() {
+		t.Error("ExcludeUnfavorited filter should not be nil")
+	}
+	if !f.Matches(fav) || f.Matches(unfav) {
+		t.Error("ExcludeUnfavorited filter should match only favorite artists")
+	}
+
+	f = NewArtistFilter(ArtistFilterOptions{MinAlbumCount: 2})
+	if !f.Matches(fav) || f.Matches(unfav) {
+		t.Error("MinAlbumCount filter should match only artists with enough albums")
+	}
+
+	if c := f.Clone(); c.Options().MinAlbumCount != 2 {
+		t.Error("Clone should preserve MinAlbumCount")
+	}
+}
+
+func TestAlbumFilter_ArtistIDs(t *testing.T) {
+	f := NewAlbumFilter(AlbumFilterOptions{ArtistIDs: []string{"ar-1", "ar-2"}})
+	if f.IsNil() {
+		t.Error("ArtistIDs filter should not be nil")
+	}
+	if !f.Matches(&Album{ArtistIDs: []string{"ar-3", "ar-2"}}) {
+		t.Error("ArtistIDs filter should match album by any of the artists")
+	}
+	if f.Matches(&Album{ArtistIDs: []string{"ar-3"}}) {
+		t.Error("ArtistIDs filter should not match album by other artists")
+	}
+
+	c := f.Clone()
+	f.Options().ArtistIDs[0] = "changed"
+	if c.Options().ArtistIDs[0] != "ar-1" {
+		t.Error("Clone should deep copy ArtistIDs")
+	}
+}
+
+func TestAlbumFilter_ReleaseTypes(t *testing.T) {
+	f := NewAlbumFilter(AlbumFilterOptions{ReleaseTypes: ReleaseTypeEP | ReleaseTypeLive})
+	if f.IsNil() {
+		t.Error("ReleaseTypes filter should not be nil")
+	}
+	if !f.Matches(&Album{ReleaseTypes: ReleaseTypeLive | ReleaseTypeAlbum}) {
+		t.Error("ReleaseTypes filter should match album with any requested type")
+	}
+	if f.Matches(&Album{ReleaseTypes: ReleaseTypeAlbum}) {
+		t.Error("ReleaseTypes filter should not match album of other types")
+	}
+
+	f = NewAlbumFilter(AlbumFilterOptions{ReleaseTypes: ReleaseTypeAlbum})
+	if !f.Matches(&Album{ReleaseTypes: ReleaseTypeAlbum}) || !f.Matches(&Album{}) {
+		t.Error("ReleaseTypeAlbum filter should match plain and untyped albums")
+	}
+}
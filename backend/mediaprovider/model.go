@@ -1,6 +1,10 @@
 package mediaprovider
 
-import "time"
+import (
+	"io"
+	"strings"
+	"time"
+)
 
 // Bit field flag for the ReleaseTypes property
 type ReleaseType = int32
@@ -51,6 +55,10 @@ type Album struct {
 	TrackCount   int
 	Favorite     bool
 	ReleaseTypes ReleaseTypes
+	PlayCount    int
+	LastPlayed   time.Time
+	Created      time.Time // when the album was added to the library
+	Explicit     bool      // false if not reported by the server
 }
 
 func (a *Album) YearOrZero() int {
@@ -65,6 +73,165 @@ type AlbumWithTracks struct {
 	Tracks []*Track
 }
 
+// A set of an album's tracks credited to the same artist
+type TrackGroup struct {
+	ArtistName string
+	Tracks     []*Track
+}
+
+// Selects the rows of the home screen to fetch, by the number
+// of albums to include in each. A count of 0 skips the row.
+type HomeScreenOptions struct {
+	RecentlyAddedCount  int
+	RecentlyPlayedCount int
+	RandomCount         int
+	FavoritesCount      int
+}
+
+type HomeScreen struct {
+	RecentlyAdded  HomeScreenRow
+	RecentlyPlayed HomeScreenRow
+	Random         HomeScreenRow
+	Favorites      HomeScreenRow
+}
+
+type HomeScreenRow struct {
+	Albums []*Album
+	Err    error // set if the row failed to load
+}
+
+type LibrarySectionType int
+
+const (
+	LibrarySectionMusic LibrarySectionType = iota
+	LibrarySectionAudiobooks
+	LibrarySectionPodcasts
+)
+
+type ScanStatus struct {
+	Scanning bool
+	Count    int64 // number of items scanned so far
+}
+
+type ChatMessage struct {
+	Username string
+	Text     string
+	Time     time.Time
+}
+
+// A track currently being played by a user of the server.
+type NowPlayingEntry struct {
+	Track      *Track
+	Username   string
+	PlayerName string
+	MinutesAgo int // since playback started
+}
+
+// A group of top-level directories sharing an index
+// (e.g. first letter), for folder-based browsing.
+type DirectoryIndex struct {
+	Name        string
+	Directories []*DirectoryEntry
+}
+
+// A reference to a directory, for folder-based browsing.
+type DirectoryEntry struct {
+	ID         string
+	Name       string
+	CoverArtID string
+}
+
+// The contents of a directory, for folder-based browsing.
+type Directory struct {
+	ID          string
+	ParentID    string // "" for top-level directories
+	Name        string
+	Directories []*DirectoryEntry
+	Tracks      []*Track
+}
+
+type MusicFolder struct {
+	ID   string
+	Name string
+}
+
+// A top-level library folder on the server and the
+// predominant type of media it contains.
+type LibrarySection struct {
+	ID   string
+	Name string
+	Type LibrarySectionType
+}
+
+type PodcastChannel struct {
+	ID          string
+	Title       string
+	Description string
+	URL         string
+	CoverArtID  string
+}
+
+type PodcastEpisodeStatus int
+
+const (
+	PodcastEpisodeNew PodcastEpisodeStatus = iota // not downloaded by the server
+	PodcastEpisodeDownloading
+	PodcastEpisodeCompleted
+	PodcastEpisodeError
+	PodcastEpisodeDeleted
+	PodcastEpisodeSkipped
+)
+
+type PodcastEpisode struct {
+	ID          string
+	ChannelID   string
+	Title       string
+	Description string
+	PublishDate time.Time
+	Duration    int
+	CoverArtID  string
+	Status      PodcastEpisodeStatus
+	StreamID    string // ID to stream the episode with; empty unless downloaded
+}
+
+// A public share link
+type Share struct {
+	ID          string
+	URL         string
+	Description string
+	Expires     time.Time // zero if the share doesn't expire
+	VisitCount  int
+}
+
+// A saved resume position within a track
+type Bookmark struct {
+	*Track
+	PositionSeconds int
+	Comment         string
+	Changed         time.Time
+}
+
+type PlayHistoryEntry struct {
+	Track    *Track
+	PlayedAt time.Time
+}
+
+// Aggregate listening statistics for a date range.
+// See helpers.GetListeningStats for how these are approximated.
+type ListeningStats struct {
+	TotalPlays int
+	TotalTime  time.Duration
+	TopArtists []ArtistPlayCount
+	TopAlbums  []*Album
+	TopTracks  []*Track
+}
+
+type ArtistPlayCount struct {
+	ArtistID   string
+	ArtistName string
+	PlayCount  int
+}
+
 type AlbumInfo struct {
 	Notes         string
 	LastFmUrl     string
@@ -120,10 +287,16 @@ type Track struct {
 	LastPlayed    time.Time
 	FilePath      string
 	BitRate       int
+	SampleRate    int // Hz; 0 if not reported by the server
 	ContentType   string
 	Comment       string
 	BPM           int
-	ReplayGain    ReplayGainInfo
+	ReplayGain    *ReplayGainInfo // nil if not reported by the server
+	Explicit      bool            // false if not reported by the server
+
+	// Set for placeholder tracks representing playlist entries that
+	// are no longer available on the server. Only ID is populated.
+	Missing bool
 }
 
 type ReplayGainInfo struct {
@@ -142,6 +315,10 @@ type Playlist struct {
 	Owner       string
 	Duration    int
 	TrackCount  int
+
+	// When the playlist was last modified, or created if the server
+	// doesn't report modifications. Zero if unknown.
+	ChangedSince time.Time
 }
 
 type PlaylistWithTracks struct {
@@ -149,13 +326,35 @@ type PlaylistWithTracks struct {
 	Tracks []*Track
 }
 
+// A track being downloaded as part of a batch download.
+// If Err is non-nil, Reader is nil and the track was skipped.
+// Otherwise the receiver must close Reader when done with it.
+type DownloadedTrack struct {
+	Track    *Track
+	Reader   io.ReadCloser
+	FileName string // suggested file name
+	Err      error
+}
+
 type Lyrics struct {
 	Title  string
 	Artist string
-	Synced bool
+	Synced bool // if false, the Start times of Lines are unset
 	Lines  []LyricLine
 }
 
+// PlainText returns the lyrics as newline-separated text, without timings.
+func (l *Lyrics) PlainText() string {
+	var sb strings.Builder
+	for i, line := range l.Lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(line.Text)
+	}
+	return sb.String()
+}
+
 type LyricLine struct {
 	Text  string
 	Start float64 // seconds
@@ -174,6 +373,54 @@ type RadioStation struct {
 	StreamURL   string
 }
 
+// Transcoding options for a stream URL.
+type StreamOptions struct {
+	// Stream the original file. Format and MaxBitRate are ignored.
+	ForceRaw bool
+
+	// Target format, e.g. "mp3" or "opus". "" for the server default.
+	Format string
+
+	// Max bit rate (kbps). 0 for the user's server-configured limit.
+	MaxBitRate int
+}
+
+type User struct {
+	Username   string
+	Email      string
+	MaxBitRate int // kbps, 0 == unlimited
+
+	AdminRole    bool
+	SettingsRole bool
+	DownloadRole bool
+	UploadRole   bool
+	PlaylistRole bool
+	CoverArtRole bool
+	CommentRole  bool
+	PodcastRole  bool
+	StreamRole   bool
+	JukeboxRole  bool
+	ShareRole    bool
+}
+
+type CreateUserParams struct {
+	Username string // required
+	Password string // required
+	Email    string // required
+
+	AdminRole    bool
+	SettingsRole bool
+	DownloadRole bool
+	UploadRole   bool
+	PlaylistRole bool
+	CoverArtRole bool
+	CommentRole  bool
+	PodcastRole  bool
+	StreamRole   bool
+	JukeboxRole  bool
+	ShareRole    bool
+}
+
 type MediaItemType int
 
 const (
@@ -1,9 +1,12 @@
 package subsonic
 
 import (
+	"context"
+	"encoding/xml"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dweymouth/supersonic/backend/mediaprovider"
 	"github.com/dweymouth/supersonic/backend/mediaprovider/helpers"
@@ -21,6 +24,8 @@ func (s *subsonicMediaProvider) AlbumSortOrders() []string {
 		mediaprovider.AlbumSortArtistAZ,
 		mediaprovider.AlbumSortYearAscending,
 		mediaprovider.AlbumSortYearDescending,
+		mediaprovider.AlbumSortHighestRated,
+		mediaprovider.AlbumSortFavorites,
 	}
 }
 
@@ -35,6 +40,12 @@ func filterAlbumMatches(f mediaprovider.AlbumFilter, album *subsonic.AlbumID3, i
 	if filterOptions.ExcludeUnfavorited && album.Starred.IsZero() {
 		return false
 	}
+	if filterOptions.RequireCoverArt && album.CoverArt == "" {
+		return false
+	}
+	if filterOptions.RequireNoCoverArt && album.CoverArt != "" {
+		return false
+	}
 	if y := album.Year; y < filterOptions.MinYear || (filterOptions.MaxYear > 0 && y > filterOptions.MaxYear) {
 		return false
 	}
@@ -50,9 +61,25 @@ func filterAlbumMatches(f mediaprovider.AlbumFilter, album *subsonic.AlbumID3, i
 }
 
 func (s *subsonicMediaProvider) IterateAlbums(sortOrder string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
+	return s.iterateAlbums(context.Background(), sortOrder, filter)
+}
+
+// SupportsContext interface
+var _ mediaprovider.SupportsContext = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) IterateAlbumsContext(ctx context.Context, sortOrder string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
+	iter := s.iterateAlbums(ctx, sortOrder, filter)
+	if iter == nil {
+		return nil
+	}
+	return helpers.NewContextIterator(ctx, iter)
+}
+
+func (s *subsonicMediaProvider) iterateAlbums(ctx context.Context, sortOrder string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
 	filterOptions := filter.Options()
-	if sortOrder == "" && len(filterOptions.Genres) == 1 {
-		genre := filterOptions.Genres[0]
+	// byGenre lists can't be sorted, so for other sort orders
+	// genres are filtered client-side from the sorted list
+	if sortOrder == "" && len(filterOptions.Genres) > 0 {
 		// The Subsonic API (non-OpenSubsonic) returns only the first genre for multi-genre albums,
 		// but servers do internally match against all the genres the album is categorized with.
 		// So we must not additionally filter by genre to avoid excluding results where
@@ -61,53 +88,99 @@ func (s *subsonicMediaProvider) IterateAlbums(sortOrder string, filter mediaprov
 		modifiedOptions := modifiedFilter.Options()
 		modifiedOptions.Genres = nil
 		modifiedFilter.SetOptions(modifiedOptions)
-		fetchFn := func(offset, limit int) ([]*subsonic.AlbumID3, error) {
-			return s.client.GetAlbumList2("byGenre",
-				map[string]string{"genre": genre, "offset": strconv.Itoa(offset), "limit": strconv.Itoa(limit)})
+		if len(filterOptions.Genres) == 1 {
+			return s.byGenreIter(ctx, filterOptions.Genres[0], modifiedFilter)
 		}
-		return helpers.NewAlbumIterator(makeFetchFn(fetchFn), modifiedFilter, s.prefetchCoverCB)
+		// run one server-side query per genre, rather than
+		// filtering a scan of the full library client-side
+		iters := sharedutil.MapSlice(filterOptions.Genres, func(genre string) mediaprovider.AlbumIterator {
+			return s.byGenreIter(ctx, genre, modifiedFilter)
+		})
+		return helpers.NewMergedAlbumIterator(iters...)
 	}
 	if sortOrder == "" && filterOptions.ExcludeUnfavorited {
 		modifiedFilter := filter.Clone()
 		modifiedOptions := modifiedFilter.Options()
 		modifiedOptions.ExcludeUnfavorited = false // we're already filtering by this
 		modifiedFilter.SetOptions(modifiedOptions)
-		return s.baseIterFromSimpleSortOrder("starred", modifiedFilter)
+		return s.baseIterFromSimpleSortOrder(ctx, "starred", modifiedFilter)
+	}
+	// let the server filter by year range, in ascending order
+	if sortOrder == "" && (filterOptions.MinYear > 0 || filterOptions.MaxYear > 0) {
+		sortOrder = mediaprovider.AlbumSortYearAscending
 	}
 	if sortOrder == "" {
 		sortOrder = mediaprovider.AlbumSortRecentlyAdded // default
 	}
 	switch sortOrder {
 	case mediaprovider.AlbumSortRecentlyAdded:
-		return s.baseIterFromSimpleSortOrder("newest", filter)
+		return s.baseIterFromSimpleSortOrder(ctx, "newest", filter)
 	case mediaprovider.AlbumSortRecentlyPlayed:
-		return s.baseIterFromSimpleSortOrder("recent", filter)
+		return s.baseIterFromSimpleSortOrder(ctx, "recent", filter)
 	case mediaprovider.AlbumSortFrequentlyPlayed:
-		return s.baseIterFromSimpleSortOrder("frequent", filter)
+		return s.baseIterFromSimpleSortOrder(ctx, "frequent", filter)
 	case mediaprovider.AlbumSortRandom:
-		return s.newRandomIter(filter, s.prefetchCoverCB)
+		return s.newRandomIter(ctx, filter, s.prefetchCoverCB)
 	case mediaprovider.AlbumSortTitleAZ:
-		return s.baseIterFromSimpleSortOrder("alphabeticalByName", filter)
+		return s.baseIterFromSimpleSortOrder(ctx, "alphabeticalByName", filter)
 	case mediaprovider.AlbumSortArtistAZ:
-		return s.baseIterFromSimpleSortOrder("alphabeticalByArtist", filter)
+		return s.baseIterFromSimpleSortOrder(ctx, "alphabeticalByArtist", filter)
+	case mediaprovider.AlbumSortHighestRated:
+		return s.baseIterFromSimpleSortOrder(ctx, "highest", filter)
+	case mediaprovider.AlbumSortFavorites:
+		modifiedFilter := filter.Clone()
+		modifiedOptions := modifiedFilter.Options()
+		modifiedOptions.ExcludeUnfavorited = false // all starred albums are favorites
+		modifiedFilter.SetOptions(modifiedOptions)
+		return s.baseIterFromSimpleSortOrder(ctx, "starred", modifiedFilter)
 	case mediaprovider.AlbumSortYearAscending:
-		fetchFn := func(offset, limit int) ([]*subsonic.AlbumID3, error) {
-			return s.client.GetAlbumList2("byYear",
-				map[string]string{"fromYear": "0", "toYear": "3000", "offset": strconv.Itoa(offset), "limit": strconv.Itoa(limit)})
-		}
-		return helpers.NewAlbumIterator(makeFetchFn(fetchFn), filter, s.prefetchCoverCB)
+		from, to := albumYearRange(filterOptions)
+		return s.byYearIter(ctx, from, to, filter)
 	case mediaprovider.AlbumSortYearDescending:
-		fetchFn := func(offset, limit int) ([]*subsonic.AlbumID3, error) {
-			return s.client.GetAlbumList2("byYear",
-				map[string]string{"fromYear": "3000", "toYear": "0", "offset": strconv.Itoa(offset), "limit": strconv.Itoa(limit)})
-		}
-		return helpers.NewAlbumIterator(makeFetchFn(fetchFn), filter, s.prefetchCoverCB)
+		// Subsonic lists years in descending order if fromYear > toYear
+		from, to := albumYearRange(filterOptions)
+		return s.byYearIter(ctx, to, from, filter)
 	default:
 		log.Printf("Undefined album sort order: %s", sortOrder)
 		return nil
 	}
 }
 
+// albumYearRange returns the (ascending) range of years
+// to request from the server for the filter's year bounds.
+func albumYearRange(opts mediaprovider.AlbumFilterOptions) (from, to int) {
+	from, to = 0, 3000
+	if opts.MinYear > 0 {
+		from = opts.MinYear
+	}
+	if opts.MaxYear > 0 {
+		to = opts.MaxYear
+	}
+	return from, to
+}
+
+func (s *subsonicMediaProvider) byGenreIter(ctx context.Context, genre string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
+	fetchFn := func(offset, limit int) ([]*mediaprovider.Album, error) {
+		// getAlbumList2 pages by "size", not "limit"; servers ignore
+		// the latter and return their default page size of 10
+		return s.getAlbumList2("byGenre", s.withMusicFolder(
+			map[string]string{"genre": genre, "offset": strconv.Itoa(offset), "size": strconv.Itoa(limit)}))
+	}
+	return helpers.NewAlbumIterator(s.makeFetchFn(ctx, fetchFn), filter, s.prefetchCoverCB)
+}
+
+func (s *subsonicMediaProvider) byYearIter(ctx context.Context, fromYear, toYear int, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
+	fetchFn := func(offset, limit int) ([]*mediaprovider.Album, error) {
+		return s.getAlbumList2("byYear", s.withMusicFolder(map[string]string{
+			"fromYear": strconv.Itoa(fromYear),
+			"toYear":   strconv.Itoa(toYear),
+			"offset":   strconv.Itoa(offset),
+			"size":     strconv.Itoa(limit),
+		}))
+	}
+	return helpers.NewAlbumIterator(s.makeFetchFn(ctx, fetchFn), filter, s.prefetchCoverCB)
+}
+
 func (s *subsonicMediaProvider) SearchAlbums(searchQuery string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
 	return s.newSearchAlbumIter(searchQuery, filter, s.prefetchCoverCB)
 }
@@ -210,35 +283,122 @@ func (s *searchAlbumIter) addNewAlbums(al []*subsonic.AlbumID3) {
 	}
 }
 
-func (s *subsonicMediaProvider) newRandomIter(filter mediaprovider.AlbumFilter, cb func(string)) mediaprovider.AlbumIterator {
+func (s *subsonicMediaProvider) newRandomIter(ctx context.Context, filter mediaprovider.AlbumFilter, cb func(string)) mediaprovider.AlbumIterator {
 	return helpers.NewRandomAlbumIter(
-		s.fetchFnFromStandardSort("newest"),
-		makeFetchFn(func(offset, limit int) ([]*subsonic.AlbumID3, error) {
+		s.fetchFnFromStandardSort(ctx, "newest"),
+		s.makeFetchFn(ctx, func(offset, limit int) ([]*mediaprovider.Album, error) {
 			args := map[string]string{
 				"size":   strconv.Itoa(limit),
 				"offset": strconv.Itoa(offset),
 			}
-			return s.client.GetAlbumList2("random", args)
+			return s.getAlbumList2("random", s.withMusicFolder(args))
 		}),
 		filter, s.prefetchCoverCB)
 }
 
-func (s *subsonicMediaProvider) baseIterFromSimpleSortOrder(sort string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
-	return helpers.NewAlbumIterator(s.fetchFnFromStandardSort(sort), filter, s.prefetchCoverCB)
+func (s *subsonicMediaProvider) baseIterFromSimpleSortOrder(ctx context.Context, sort string, filter mediaprovider.AlbumFilter) mediaprovider.AlbumIterator {
+	return helpers.NewAlbumIterator(s.fetchFnFromStandardSort(ctx, sort), filter, s.prefetchCoverCB)
 }
 
-func (s *subsonicMediaProvider) fetchFnFromStandardSort(sort string) helpers.AlbumFetchFn {
-	return makeFetchFn(func(offset, limit int) ([]*subsonic.AlbumID3, error) {
-		return s.client.GetAlbumList2(sort, map[string]string{"size": strconv.Itoa(limit), "offset": strconv.Itoa(offset)})
+func (s *subsonicMediaProvider) fetchFnFromStandardSort(ctx context.Context, sort string) helpers.AlbumFetchFn {
+	return s.makeFetchFn(ctx, func(offset, limit int) ([]*mediaprovider.Album, error) {
+		return s.getAlbumList2(sort, s.withMusicFolder(
+			map[string]string{"size": strconv.Itoa(limit), "offset": strconv.Itoa(offset)}))
 	})
 }
 
-func makeFetchFn(subsonicFetchFn func(offset, limit int) ([]*subsonic.AlbumID3, error)) helpers.AlbumFetchFn {
+func (s *subsonicMediaProvider) makeFetchFn(ctx context.Context, fetchFn helpers.AlbumFetchFn) helpers.AlbumFetchFn {
 	return func(offset, limit int) ([]*mediaprovider.Album, error) {
-		al, err := subsonicFetchFn(offset, limit)
+		return retryGet(ctx, s, func() ([]*mediaprovider.Album, error) {
+			return helpers.DoContext(ctx, func() ([]*mediaprovider.Album, error) {
+				return fetchFn(offset, limit)
+			})
+		})
+	}
+}
+
+// The go-subsonic AlbumID3 type doesn't decode the OpenSubsonic "played"
+// attribute, so getAlbumList2 responses are decoded into these.
+type albumList2Response struct {
+	AlbumList2 struct {
+		Album []*playedAlbumID3 `xml:"album"`
+	} `xml:"albumList2"`
+}
+
+type playedAlbumID3 struct {
+	*subsonic.AlbumID3
+	Played time.Time
+}
+
+func (a *playedAlbumID3) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "played" {
+			a.Played = parseDateTime(attr.Value)
+		}
+	}
+	a.AlbumID3 = &subsonic.AlbumID3{}
+	return d.DecodeElement(a.AlbumID3, &start)
+}
+
+// getAlbumList2 fetches an album list like the client's GetAlbumList2,
+// but with the albums' LastPlayed set from their "played" attribute.
+func (s *subsonicMediaProvider) getAlbumList2(listType string, params map[string]string) ([]*mediaprovider.Album, error) {
+	params["type"] = listType
+	var resp albumList2Response
+	if err := s.getRaw("getAlbumList2", params, &resp); err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(resp.AlbumList2.Album, func(a *playedAlbumID3) *mediaprovider.Album {
+		album := toAlbum(a.AlbumID3)
+		album.LastPlayed = a.Played
+		return album
+	}), nil
+}
+
+// SupportsAlbumCount interface
+var _ mediaprovider.SupportsAlbumCount = (*subsonicMediaProvider)(nil)
+
+// CountAlbums returns the number of albums matching the filter. The Subsonic
+// API has no album count query, so a total is only available for the nil
+// filter, summed from the artists' album counts (counting albums with
+// multiple album artists once for each), and for a filter on a single
+// genre, from the genre's album count. Returns -1 for other filters.
+// Returns ErrNotSupported for a filter on explicit content.
+func (s *subsonicMediaProvider) CountAlbums(filter mediaprovider.AlbumFilter) (int, error) {
+	if filter != nil && filter.Options().ExcludeExplicit {
+		return 0, mediaprovider.ErrNotSupported
+	}
+	if filter == nil || filter.IsNil() {
+		idxs, err := apiResult(s.client.GetArtists(s.withMusicFolder(map[string]string{})))
 		if err != nil {
-			return nil, err
+			return 0, err
+		}
+		var count int
+		if idxs != nil {
+			for _, idx := range idxs.Index {
+				for _, ar := range idx.Artist {
+					count += ar.AlbumCount
+				}
+			}
+		}
+		return count, nil
+	}
+
+	// genre album counts aren't scoped to the active music folder
+	opts := filter.Options()
+	rest := opts.Clone()
+	rest.Genres = nil
+	if len(opts.Genres) != 1 || !mediaprovider.NewAlbumFilter(rest).IsNil() || s.activeMusicFolderID != "" {
+		return -1, nil
+	}
+	genres, err := s.GetGenres()
+	if err != nil {
+		return 0, err
+	}
+	for _, g := range genres {
+		if g.Name == opts.Genres[0] {
+			return g.AlbumCount, nil
 		}
-		return sharedutil.MapSlice(al, toAlbum), nil
 	}
+	return 0, nil
 }
@@ -1,9 +1,12 @@
 package subsonic
 
 import (
+	"context"
 	"log"
 	"math/rand"
 	"slices"
+	"strconv"
+	"time"
 
 	"golang.org/x/text/collate"
 	"golang.org/x/text/language"
@@ -19,6 +22,8 @@ func (s *subsonicMediaProvider) ArtistSortOrders() []string {
 		mediaprovider.ArtistSortAlbumCount,
 		mediaprovider.ArtistSortNameAZ,
 		mediaprovider.ArtistSortRandom,
+		mediaprovider.ArtistSortFrequentlyPlayed,
+		mediaprovider.ArtistSortRecentlyPlayed,
 	}
 }
 
@@ -26,7 +31,7 @@ func filterArtistMatches(f mediaprovider.ArtistFilter, artist *subsonic.ArtistID
 	if artist == nil {
 		return false
 	}
-	return true
+	return f == nil || f.Matches(toArtistFromID3(artist))
 }
 
 func (s *subsonicMediaProvider) IterateArtists(sortOrder string, filter mediaprovider.ArtistFilter) mediaprovider.ArtistIterator {
@@ -71,6 +76,31 @@ func (s *subsonicMediaProvider) IterateArtists(sortOrder string, filter mediapro
 			},
 			filter,
 		)
+	case mediaprovider.ArtistSortFrequentlyPlayed:
+		// Approximate: the Subsonic API has no per-artist play counts,
+		// so aggregate the play counts of the most played albums
+		return s.baseArtistIterFromSimpleSortOrder(
+			func(artists []*subsonic.ArtistID3) []*subsonic.ArtistID3 {
+				stats := s.getArtistPlayStats()
+				slices.SortStableFunc(artists, func(a, b *subsonic.ArtistID3) int {
+					return stats[b.ID].playCount - stats[a.ID].playCount
+				})
+				return artists
+			},
+			filter,
+		)
+	case mediaprovider.ArtistSortRecentlyPlayed:
+		// Approximate: derived from the most recently played albums
+		return s.baseArtistIterFromSimpleSortOrder(
+			func(artists []*subsonic.ArtistID3) []*subsonic.ArtistID3 {
+				stats := s.getArtistPlayStats()
+				slices.SortStableFunc(artists, func(a, b *subsonic.ArtistID3) int {
+					return stats[b.ID].recency - stats[a.ID].recency
+				})
+				return artists
+			},
+			filter,
+		)
 	default:
 		log.Printf("Undefined artist sort order: %s", sortOrder)
 		return nil
@@ -165,7 +195,9 @@ func (s *subsonicMediaProvider) artistFetchFnFromStandardSort(sortFn func([]*sub
 			return nil, nil
 		}
 
-		idxs, err := s.client.GetArtists(map[string]string{})
+		idxs, err := retryGet(context.Background(), s, func() (*subsonic.ArtistsID3, error) {
+			return s.client.GetArtists(s.withMusicFolder(map[string]string{}))
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -180,6 +212,81 @@ func (s *subsonicMediaProvider) artistFetchFnFromStandardSort(sortFn func([]*sub
 	})
 }
 
+// max number of albums from each of the "frequent" and "recent"
+// album lists used to derive artist play statistics
+const artistPlayStatsMaxAlbums = 500
+
+type artistPlayStats struct {
+	playCount int
+	// rank of the artist's most recently played album in the "recent"
+	// list, counted from the end (higher is more recent; 0 if absent),
+	// as album list entries don't include when they were played
+	recency int
+}
+
+// getArtistPlayStats returns play statistics per artist ID, aggregated from
+// the server's most frequently and most recently played albums.
+// Artists with no albums in either list will be absent from the map.
+func (s *subsonicMediaProvider) getArtistPlayStats() map[string]artistPlayStats {
+	s.playStatsLock.Lock()
+	defer s.playStatsLock.Unlock()
+	s.refreshPlayStats()
+	return s.artistPlayStatsCached
+}
+
+// getGenrePlayCounts returns play counts per genre name, aggregated
+// in the same way as getArtistPlayStats.
+func (s *subsonicMediaProvider) getGenrePlayCounts() map[string]int {
+	s.playStatsLock.Lock()
+	defer s.playStatsLock.Unlock()
+	s.refreshPlayStats()
+	return s.genrePlayCountsCached
+}
+
+// refreshPlayStats rebuilds the play statistics caches if they are stale.
+// The caller must hold playStatsLock. The cached maps are replaced rather
+// than modified, so they may be read after the lock is released.
+func (s *subsonicMediaProvider) refreshPlayStats() {
+	if s.artistPlayStatsCached != nil && time.Now().Unix()-s.artistPlayStatsCachedAt < cacheValidDurationSeconds {
+		return
+	}
+
+	// the same album may be returned in both lists; count it only once
+	albums := make(map[string]*subsonic.AlbumID3)
+	recency := make(map[string]int) // album ID -> rank from end of "recent" list
+	for _, sort := range []string{"frequent", "recent"} {
+		al, err := apiResult(s.client.GetAlbumList2(sort, map[string]string{"size": strconv.Itoa(artistPlayStatsMaxAlbums)}))
+		if err != nil {
+			log.Printf("error fetching %s albums: %s", sort, err.Error())
+			continue
+		}
+		for i, a := range al {
+			albums[a.ID] = a
+			if sort == "recent" {
+				recency[a.ID] = len(al) - i
+			}
+		}
+	}
+
+	stats := make(map[string]artistPlayStats)
+	genreCounts := make(map[string]int)
+	for _, al := range albums {
+		album := toAlbum(al)
+		for _, id := range album.ArtistIDs {
+			st := stats[id]
+			st.playCount += album.PlayCount
+			st.recency = max(st.recency, recency[album.ID])
+			stats[id] = st
+		}
+		for _, g := range album.Genres {
+			genreCounts[g] += album.PlayCount
+		}
+	}
+	s.artistPlayStatsCached = stats
+	s.genrePlayCountsCached = genreCounts
+	s.artistPlayStatsCachedAt = time.Now().Unix()
+}
+
 func makeArtistFetchFn(subsonicFetchFn func(offset, limit int) ([]*subsonic.ArtistID3, error)) helpers.ArtistFetchFn {
 	return func(offset, limit int) ([]*mediaprovider.Artist, error) {
 		ar, err := subsonicFetchFn(offset, limit)
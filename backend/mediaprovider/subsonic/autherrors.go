@@ -0,0 +1,43 @@
+package subsonic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// Prefixes of the Subsonic API errors that apiErr maps to sentinel errors.
+const (
+	errCodeWrongCredentialsPrefix = "Error #40:"
+	errCodeNotAuthorizedPrefix    = "Error #50:"
+)
+
+// apiErr wraps an error returned by a go-subsonic client call that reports
+// a credentials or permission failure with mediaprovider.ErrAuthFailed or
+// mediaprovider.ErrNotAuthorized, so that every provider call surfaces them
+// distinctly from other failures. Other errors are returned unchanged.
+//
+// The mapping is done on the decoded API error, rather than in the HTTP
+// transport, since errors returned from a transport are wrapped in a
+// url.Error that includes the request URL and its credentials.
+func apiErr(err error) error {
+	if err == nil || errors.Is(err, mediaprovider.ErrAuthFailed) || errors.Is(err, mediaprovider.ErrNotAuthorized) {
+		return err
+	}
+	msg := strings.TrimSpace(err.Error())
+	switch {
+	case strings.HasPrefix(msg, errCodeWrongCredentialsPrefix):
+		return fmt.Errorf("%w: %s", mediaprovider.ErrAuthFailed, msg)
+	case strings.HasPrefix(msg, errCodeNotAuthorizedPrefix):
+		return fmt.Errorf("%w: %s", mediaprovider.ErrNotAuthorized, msg)
+	}
+	return err
+}
+
+// apiResult applies apiErr to the error of a go-subsonic client call
+// that also returns a value.
+func apiResult[T any](v T, err error) (T, error) {
+	return v, apiErr(err)
+}
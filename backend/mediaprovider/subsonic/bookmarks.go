@@ -0,0 +1,50 @@
+package subsonic
+
+import (
+	"strconv"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsBookmarks = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetBookmarks() ([]*mediaprovider.Bookmark, error) {
+	resp, err := apiResult(s.client.Get("getBookmarks", nil))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Bookmarks == nil {
+		return nil, nil
+	}
+	bookmarks := resp.Bookmarks.Bookmark
+	bookmarks = sharedutil.FilterSlice(bookmarks, func(b *subsonic.Bookmark) bool {
+		return b.Entry != nil
+	})
+	return sharedutil.MapSlice(bookmarks, func(b *subsonic.Bookmark) *mediaprovider.Bookmark {
+		return &mediaprovider.Bookmark{
+			Track:           toTrack(b.Entry),
+			PositionSeconds: int(b.Position / 1000),
+			Comment:         b.Comment,
+			Changed:         b.Changed,
+		}
+	}), nil
+}
+
+func (s *subsonicMediaProvider) CreateBookmark(trackID string, positionSeconds int, comment string) error {
+	params := map[string]string{
+		"id":       trackID,
+		"position": strconv.Itoa(positionSeconds * 1000), // millis
+	}
+	if comment != "" {
+		params["comment"] = comment
+	}
+	_, err := apiResult(s.client.Get("createBookmark", params))
+	return err
+}
+
+func (s *subsonicMediaProvider) DeleteBookmark(trackID string) error {
+	_, err := apiResult(s.client.Get("deleteBookmark", map[string]string{"id": trackID}))
+	return err
+}
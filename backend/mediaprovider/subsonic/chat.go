@@ -0,0 +1,51 @@
+package subsonic
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsChat = (*subsonicMediaProvider)(nil)
+
+// GetChatMessages returns the server's chat messages posted after since.
+// Message times are converted from the server's clock to the local clock,
+// so the time of the last message received can be passed as since when polling.
+func (s *subsonicMediaProvider) GetChatMessages(since time.Time) ([]*mediaprovider.ChatMessage, error) {
+	params := map[string]string{}
+	if !since.IsZero() {
+		params["since"] = strconv.FormatInt(s.toServerTime(since).UnixMilli(), 10)
+	}
+	msgs, err := s.getChatMessages(params)
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(msgs, func(m *subsonic.ChatMessage) *mediaprovider.ChatMessage {
+		return &mediaprovider.ChatMessage{
+			Username: m.Username,
+			Text:     m.Message,
+			Time:     time.UnixMilli(m.Time).Add(-s.ServerClockSkew()),
+		}
+	}), nil
+}
+
+// getChatMessages fetches the server chat, which
+// the go-subsonic client has no typed method for.
+func (s *subsonicMediaProvider) getChatMessages(params map[string]string) ([]*subsonic.ChatMessage, error) {
+	resp, err := apiResult(s.client.Get("getChatMessages", params))
+	if err != nil {
+		return nil, err
+	}
+	if resp.ChatMessages == nil {
+		return nil, nil
+	}
+	return resp.ChatMessages.ChatMessage, nil
+}
+
+func (s *subsonicMediaProvider) SendChatMessage(text string) error {
+	_, err := apiResult(s.client.Get("addChatMessage", map[string]string{"message": text}))
+	return err
+}
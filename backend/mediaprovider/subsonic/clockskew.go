@@ -0,0 +1,46 @@
+package subsonic
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// ServerClockSkew returns how far the server's clock is ahead of the
+// local clock (negative if behind), measured once from the Date header
+// of a ping response. Since the header has a resolution of one second,
+// skews under a second are reported as 0.
+func (s *subsonicMediaProvider) ServerClockSkew() time.Duration {
+	s.clockSkewOnce.Do(func() {
+		s.clockSkew = s.measureClockSkew()
+	})
+	return s.clockSkew
+}
+
+// toServerTime converts a local time to the server's clock, for timestamps
+// such as scrobbles that are recorded in the server's history.
+func (s *subsonicMediaProvider) toServerTime(t time.Time) time.Time {
+	return t.Add(s.ServerClockSkew())
+}
+
+func (s *subsonicMediaProvider) measureClockSkew() time.Duration {
+	sent := time.Now()
+	resp, err := s.client.Request(http.MethodGet, "ping", nil)
+	if err != nil {
+		log.Printf("failed to measure server clock skew: %s", err.Error())
+		return 0
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0
+	}
+	// assume the server stamped the response halfway through the round trip
+	local := sent.Add(received.Sub(sent) / 2)
+	skew := serverTime.Sub(local)
+	if skew.Abs() < time.Second {
+		return 0
+	}
+	return skew.Round(time.Second)
+}
@@ -0,0 +1,58 @@
+package subsonic
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsCoverArtBytes = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetCoverArtBytes(id string, size int) ([]byte, string, error) {
+	params := url.Values{}
+	params.Add("id", id)
+	if size > 0 {
+		params.Add("size", strconv.Itoa(size))
+	}
+	resp, err := retryGet(context.Background(), s, func() (*http.Response, error) {
+		return s.client.Request("GET", "getCoverArt", params)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	// the server responds with a regular subsonic-response
+	// document instead of image data if the request failed
+	if strings.HasPrefix(contentType, "text/xml") || strings.HasPrefix(contentType, "application/xml") {
+		return nil, "", coverArtResponseError(data)
+	}
+	return data, contentType, nil
+}
+
+// coverArtResponseError decodes the error from a subsonic-response
+// document, formatted the same way as the go-subsonic client's errors.
+func coverArtResponseError(data []byte) error {
+	var parsed subsonic.Response
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("getCoverArt: unexpected response: %w", err)
+	}
+	if parsed.Error != nil {
+		return responseError(parsed.Error)
+	}
+	return errors.New("getCoverArt: unexpected non-image response")
+}
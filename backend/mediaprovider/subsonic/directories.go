@@ -0,0 +1,57 @@
+package subsonic
+
+import (
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsDirectoryBrowsing = (*subsonicMediaProvider)(nil)
+
+// GetIndexes returns the top-level directories of the active music
+// folder (or all folders), grouped by index. Tracks located directly
+// at the top level, which some servers also return, are not included.
+func (s *subsonicMediaProvider) GetIndexes() ([]*mediaprovider.DirectoryIndex, error) {
+	idxs, err := apiResult(s.client.GetIndexes(s.withMusicFolder(map[string]string{})))
+	if err != nil {
+		return nil, err
+	}
+	if idxs == nil {
+		return nil, nil
+	}
+	return sharedutil.MapSlice(idxs.Index, func(idx *subsonic.Index) *mediaprovider.DirectoryIndex {
+		return &mediaprovider.DirectoryIndex{
+			Name: idx.Name,
+			Directories: sharedutil.MapSlice(idx.Artist, func(ar *subsonic.Artist) *mediaprovider.DirectoryEntry {
+				return &mediaprovider.DirectoryEntry{ID: ar.ID, Name: ar.Name}
+			}),
+		}
+	}), nil
+}
+
+func (s *subsonicMediaProvider) GetMusicDirectory(id string) (*mediaprovider.Directory, error) {
+	d, err := apiResult(s.client.GetMusicDirectory(id))
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, mediaprovider.ErrNotFound
+	}
+	dir := &mediaprovider.Directory{
+		ID:       d.ID,
+		ParentID: d.Parent,
+		Name:     d.Name,
+	}
+	for _, ch := range d.Child {
+		if ch.IsDir {
+			dir.Directories = append(dir.Directories, &mediaprovider.DirectoryEntry{
+				ID:         ch.ID,
+				Name:       ch.Title,
+				CoverArtID: ch.CoverArt,
+			})
+		} else {
+			dir.Tracks = append(dir.Tracks, toTrack(ch))
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,109 @@
+package subsonic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+var _ mediaprovider.SupportsPlaylistDownload = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) DownloadPlaylist(
+	ctx context.Context,
+	playlistID string,
+	transcoded bool,
+	maxBitRate int,
+	format string,
+	progress func(done, total int),
+) (<-chan mediaprovider.DownloadedTrack, error) {
+	pl, err := s.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan mediaprovider.DownloadedTrack)
+	go func() {
+		defer close(ch)
+		total := len(pl.Tracks)
+		for i, tr := range pl.Tracks {
+			if ctx.Err() != nil {
+				return
+			}
+			dl := mediaprovider.DownloadedTrack{
+				Track:    tr,
+				FileName: downloadFileName(tr, transcoded, format),
+			}
+			if transcoded {
+				dl.Reader, dl.Err = s.streamTranscoded(tr.ID, maxBitRate, format)
+			} else {
+				dl.Reader, dl.Err = apiResult(s.client.Download(tr.ID))
+			}
+			var closed chan struct{}
+			if dl.Reader != nil {
+				closed = make(chan struct{})
+				dl.Reader = &downloadReader{ReadCloser: dl.Reader, closed: closed}
+			}
+			select {
+			case <-ctx.Done():
+				if dl.Reader != nil {
+					dl.Reader.Close()
+				}
+				return
+			case ch <- dl:
+			}
+			// don't start the next download until the consumer is done with this one
+			if closed != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-closed:
+				}
+			}
+			if progress != nil {
+				progress(i+1, total)
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// downloadReader signals closed when the consumer closes it.
+type downloadReader struct {
+	io.ReadCloser
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (d *downloadReader) Close() error {
+	err := d.ReadCloser.Close()
+	d.closeOnce.Do(func() { close(d.closed) })
+	return err
+}
+
+func (s *subsonicMediaProvider) streamTranscoded(trackID string, maxBitRate int, format string) (io.ReadCloser, error) {
+	params := make(map[string]string)
+	if maxBitRate > 0 {
+		params["maxBitRate"] = strconv.Itoa(maxBitRate)
+	}
+	if format != "" {
+		params["format"] = format
+	}
+	return apiResult(s.client.Stream(trackID, params))
+}
+
+func downloadFileName(tr *mediaprovider.Track, transcoded bool, format string) string {
+	name := filepath.Base(tr.FilePath)
+	if tr.FilePath == "" {
+		name = fmt.Sprintf("%02d - %s", tr.TrackNumber, tr.Title)
+	}
+	if transcoded && format != "" && format != "raw" {
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + "." + format
+	}
+	return name
+}
@@ -0,0 +1,44 @@
+package subsonic
+
+import (
+	"strings"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+// Subsonic API error code for "the requested data was not found".
+// The client library reports API errors only as formatted strings.
+const errCodeNotFoundPrefix = "Error #70:"
+
+var _ mediaprovider.SupportsExistenceCheck = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) AlbumExists(albumID string) (bool, error) {
+	al, err := apiResult(s.client.GetAlbum(albumID))
+	return existsResult(al != nil, err)
+}
+
+func (s *subsonicMediaProvider) ArtistExists(artistID string) (bool, error) {
+	ar, err := apiResult(s.client.GetArtist(artistID))
+	return existsResult(ar != nil, err)
+}
+
+func (s *subsonicMediaProvider) TrackExists(trackID string) (bool, error) {
+	tr, err := apiResult(s.client.GetSong(trackID))
+	return existsResult(tr != nil, err)
+}
+
+// Maps the result of a lookup to an existence check result,
+// treating a not-found API error as a false result rather than an error.
+func existsResult(found bool, err error) (bool, error) {
+	if err != nil {
+		if isNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return found, nil
+}
+
+func isNotFoundErr(err error) bool {
+	return strings.HasPrefix(err.Error(), errCodeNotFoundPrefix)
+}
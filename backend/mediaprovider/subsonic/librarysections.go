@@ -0,0 +1,90 @@
+package subsonic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+const (
+	// number of random tracks sampled to infer a music folder's type
+	librarySectionSampleSize = 20
+	// tracks longer than this without other type info are assumed spoken word
+	librarySectionLongTrackSecs = 30 * 60
+)
+
+var _ mediaprovider.SupportsLibrarySections = (*subsonicMediaProvider)(nil)
+
+// GetLibrarySections returns the server's music folders with their inferred
+// types. The inference is made once and cached for the provider's lifetime.
+func (s *subsonicMediaProvider) GetLibrarySections() ([]*mediaprovider.LibrarySection, error) {
+	s.librarySectionsLock.Lock()
+	defer s.librarySectionsLock.Unlock()
+	if s.librarySectionsCached != nil {
+		return s.librarySectionsCached, nil
+	}
+
+	folders, err := apiResult(s.client.GetMusicFolders())
+	if err != nil {
+		return nil, err
+	}
+	sections := make([]*mediaprovider.LibrarySection, 0, len(folders))
+	for _, f := range folders {
+		sample, err := apiResult(s.client.GetRandomSongs(map[string]string{
+			"size":          strconv.Itoa(librarySectionSampleSize),
+			"musicFolderId": f.ID,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, &mediaprovider.LibrarySection{
+			ID:   f.ID,
+			Name: f.Name,
+			Type: inferLibrarySectionType(sample),
+		})
+	}
+	s.librarySectionsCached = sections
+	return sections, nil
+}
+
+// inferLibrarySectionType returns the most common type among the
+// sampled tracks, defaulting to music for an empty sample or a tie.
+func inferLibrarySectionType(sample []*subsonic.Child) mediaprovider.LibrarySectionType {
+	counts := make(map[mediaprovider.LibrarySectionType]int)
+	for _, ch := range sample {
+		counts[sampleTrackType(ch)]++
+	}
+	best := mediaprovider.LibrarySectionMusic
+	for _, t := range []mediaprovider.LibrarySectionType{
+		mediaprovider.LibrarySectionAudiobooks,
+		mediaprovider.LibrarySectionPodcasts,
+	} {
+		if counts[t] > counts[best] {
+			best = t
+		}
+	}
+	return best
+}
+
+func sampleTrackType(ch *subsonic.Child) mediaprovider.LibrarySectionType {
+	switch strings.ToLower(ch.Type) {
+	case "audiobook":
+		return mediaprovider.LibrarySectionAudiobooks
+	case "podcast":
+		return mediaprovider.LibrarySectionPodcasts
+	}
+	// most servers report every file as "music",
+	// so fall back on the genre and duration
+	genre := strings.ToLower(ch.Genre)
+	switch {
+	case strings.Contains(genre, "audiobook"), strings.Contains(genre, "spoken"):
+		return mediaprovider.LibrarySectionAudiobooks
+	case strings.Contains(genre, "podcast"):
+		return mediaprovider.LibrarySectionPodcasts
+	case ch.Duration > librarySectionLongTrackSecs:
+		return mediaprovider.LibrarySectionAudiobooks
+	}
+	return mediaprovider.LibrarySectionMusic
+}
@@ -0,0 +1,36 @@
+package subsonic
+
+import (
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsActiveMusicFolder = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetMusicFolders() ([]*mediaprovider.MusicFolder, error) {
+	folders, err := apiResult(s.client.GetMusicFolders())
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(folders, func(f *subsonic.MusicFolder) *mediaprovider.MusicFolder {
+		return &mediaprovider.MusicFolder{ID: f.ID, Name: f.Name}
+	}), nil
+}
+
+// SetActiveMusicFolder scopes album and artist iteration and random
+// tracks to the given music folder. The Subsonic API doesn't scope
+// getGenres or search3 by folder, so genres and search results
+// continue to cover all folders.
+func (s *subsonicMediaProvider) SetActiveMusicFolder(id string) {
+	s.activeMusicFolderID = id
+}
+
+// withMusicFolder adds the active music folder, if any,
+// to the params of a request that supports musicFolderId.
+func (s *subsonicMediaProvider) withMusicFolder(params map[string]string) map[string]string {
+	if s.activeMusicFolderID != "" {
+		params["musicFolderId"] = s.activeMusicFolderID
+	}
+	return params
+}
@@ -0,0 +1,59 @@
+package subsonic
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+// The go-subsonic NowPlayingEntry type doesn't decode the track ID,
+// so getNowPlaying responses are decoded into these.
+type nowPlayingResponse struct {
+	NowPlaying struct {
+		Entry []*nowPlayingEntry `xml:"entry"`
+	} `xml:"nowPlaying"`
+}
+
+type nowPlayingEntry struct {
+	*subsonic.Child
+	Username   string
+	PlayerName string
+	MinutesAgo int
+}
+
+func (e *nowPlayingEntry) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "username":
+			e.Username = attr.Value
+		case "playerName":
+			e.PlayerName = attr.Value
+		case "minutesAgo":
+			e.MinutesAgo, _ = strconv.Atoi(attr.Value)
+		}
+	}
+	e.Child = &subsonic.Child{}
+	return d.DecodeElement(e.Child, &start)
+}
+
+var _ mediaprovider.SupportsNowPlaying = (*subsonicMediaProvider)(nil)
+
+// GetNowPlaying returns the tracks currently being played by users of the server.
+func (s *subsonicMediaProvider) GetNowPlaying() ([]*mediaprovider.NowPlayingEntry, error) {
+	var resp nowPlayingResponse
+	if err := s.getRaw("getNowPlaying", nil, &resp); err != nil {
+		return nil, err
+	}
+	nowPlaying := make([]*mediaprovider.NowPlayingEntry, 0, len(resp.NowPlaying.Entry))
+	for _, e := range resp.NowPlaying.Entry {
+		nowPlaying = append(nowPlaying, &mediaprovider.NowPlayingEntry{
+			Track:      toTrack(e.Child),
+			Username:   e.Username,
+			PlayerName: e.PlayerName,
+			MinutesAgo: e.MinutesAgo,
+		})
+	}
+	return nowPlaying, nil
+}
@@ -0,0 +1,43 @@
+package subsonic
+
+import (
+	"strconv"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+// OpenSubsonic extension for the getPlayHistory endpoint,
+// which the go-subsonic client doesn't support
+const playHistoryExtension = "playHistory"
+
+type playHistoryResponse struct {
+	PlayHistory struct {
+		// entries' "played" attribute is the time of the play
+		Entry []*subsonic.Child `xml:"entry"`
+	} `xml:"playHistory"`
+}
+
+var _ mediaprovider.SupportsPlayHistory = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetPlayHistory(limit int) ([]*mediaprovider.PlayHistoryEntry, error) {
+	if !s.hasExtension(playHistoryExtension) {
+		return nil, mediaprovider.ErrNotSupported
+	}
+	params := map[string]string{}
+	if limit > 0 {
+		params["count"] = strconv.Itoa(limit)
+	}
+	var resp playHistoryResponse
+	if err := s.getRaw("getPlayHistory", params, &resp); err != nil {
+		return nil, err
+	}
+	history := make([]*mediaprovider.PlayHistoryEntry, 0, len(resp.PlayHistory.Entry))
+	for _, e := range resp.PlayHistory.Entry {
+		history = append(history, &mediaprovider.PlayHistoryEntry{
+			Track:    toTrack(e),
+			PlayedAt: e.Played,
+		})
+	}
+	return history, nil
+}
@@ -0,0 +1,137 @@
+package subsonic
+
+import (
+	"errors"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+)
+
+// The go-subsonic podcast types don't decode the channel and
+// episode IDs, so getPodcasts responses are decoded into these.
+type podcastsResponse struct {
+	Podcasts struct {
+		Channel []*podcastChannel `xml:"channel"`
+	} `xml:"podcasts"`
+}
+
+type podcastChannel struct {
+	ID          string            `xml:"id,attr"`
+	URL         string            `xml:"url,attr"`
+	Title       string            `xml:"title,attr"`
+	Description string            `xml:"description,attr"`
+	CoverArt    string            `xml:"coverArt,attr"`
+	Episode     []*podcastEpisode `xml:"episode"`
+}
+
+type podcastEpisode struct {
+	ID          string `xml:"id,attr"`
+	StreamID    string `xml:"streamId,attr"`
+	ChannelID   string `xml:"channelId,attr"`
+	Title       string `xml:"title,attr"`
+	Description string `xml:"description,attr"`
+	Status      string `xml:"status,attr"`
+	PublishDate string `xml:"publishDate,attr"`
+	Duration    int    `xml:"duration,attr"`
+	CoverArt    string `xml:"coverArt,attr"`
+}
+
+var _ mediaprovider.SupportsPodcasts = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetPodcastChannels() ([]*mediaprovider.PodcastChannel, error) {
+	channels, err := s.getPodcasts(map[string]string{"includeEpisodes": "false"})
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(channels, toPodcastChannel), nil
+}
+
+func (s *subsonicMediaProvider) GetPodcastEpisodes(channelID string) ([]*mediaprovider.PodcastEpisode, error) {
+	channels, err := s.getPodcasts(map[string]string{"id": channelID, "includeEpisodes": "true"})
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 {
+		return nil, errors.New("podcast channel not found")
+	}
+	return sharedutil.MapSlice(channels[0].Episode, toPodcastEpisode), nil
+}
+
+func (s *subsonicMediaProvider) getPodcasts(params map[string]string) ([]*podcastChannel, error) {
+	var resp podcastsResponse
+	if err := s.getRaw("getPodcasts", params, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Podcasts.Channel, nil
+}
+
+func (s *subsonicMediaProvider) DownloadPodcastEpisode(episodeID string) error {
+	if err := s.checkPodcastRole(); err != nil {
+		return err
+	}
+	_, err := apiResult(s.client.Get("downloadPodcastEpisode", map[string]string{"id": episodeID}))
+	return err
+}
+
+func (s *subsonicMediaProvider) RefreshPodcasts() error {
+	if err := s.checkPodcastRole(); err != nil {
+		return err
+	}
+	_, err := apiResult(s.client.Get("refreshPodcasts", nil))
+	return err
+}
+
+func (s *subsonicMediaProvider) checkPodcastRole() error {
+	user, err := s.getCurrentUser()
+	if err != nil {
+		return err
+	}
+	if user == nil || !(user.PodcastRole || user.AdminRole) {
+		return mediaprovider.ErrNotAuthorized
+	}
+	return nil
+}
+
+func toPodcastChannel(ch *podcastChannel) *mediaprovider.PodcastChannel {
+	return &mediaprovider.PodcastChannel{
+		ID:          ch.ID,
+		Title:       ch.Title,
+		Description: ch.Description,
+		URL:         ch.URL,
+		CoverArtID:  ch.CoverArt,
+	}
+}
+
+func toPodcastEpisode(ep *podcastEpisode) *mediaprovider.PodcastEpisode {
+	episode := &mediaprovider.PodcastEpisode{
+		ID:          ep.ID,
+		ChannelID:   ep.ChannelID,
+		Title:       ep.Title,
+		Description: ep.Description,
+		PublishDate: parseDateTime(ep.PublishDate),
+		Duration:    ep.Duration,
+		CoverArtID:  ep.CoverArt,
+		Status:      toPodcastEpisodeStatus(ep.Status),
+	}
+	if episode.Status == mediaprovider.PodcastEpisodeCompleted {
+		episode.StreamID = ep.StreamID
+	}
+	return episode
+}
+
+func toPodcastEpisodeStatus(status string) mediaprovider.PodcastEpisodeStatus {
+	switch status {
+	case "downloading":
+		return mediaprovider.PodcastEpisodeDownloading
+	case "completed":
+		return mediaprovider.PodcastEpisodeCompleted
+	case "error":
+		return mediaprovider.PodcastEpisodeError
+	case "deleted":
+		return mediaprovider.PodcastEpisodeDeleted
+	case "skipped":
+		return mediaprovider.PodcastEpisodeSkipped
+	default: // "new"
+		return mediaprovider.PodcastEpisodeNew
+	}
+}
@@ -0,0 +1,52 @@
+package subsonic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+)
+
+const (
+	primeTrackBytes   = 32 * 1024
+	primeTrackTimeout = 5 * time.Second
+)
+
+var _ mediaprovider.SupportsTrackPriming = (*subsonicMediaProvider)(nil)
+
+// PrimeNextTrack issues a small range request for the start of the track's
+// stream and discards the result. It returns immediately; only an error
+// building the stream URL is reported.
+func (s *subsonicMediaProvider) PrimeNextTrack(trackID string) error {
+	u, err := s.buildStreamURL(trackID, map[string]string{})
+	if err != nil {
+		return err
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), primeTrackTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", primeTrackBytes-1))
+		if s.client.UserAgent != "" {
+			req.Header.Set("User-Agent", s.client.UserAgent)
+		}
+		httpClient := s.client.Client
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+		// servers that ignore the Range header send the whole file
+		io.CopyN(io.Discard, resp.Body, primeTrackBytes)
+	}()
+	return nil
+}
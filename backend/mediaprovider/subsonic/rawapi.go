@@ -0,0 +1,60 @@
+package subsonic
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+// getRaw issues a GET request to an endpoint whose response (or some of
+// whose attributes) the go-subsonic client doesn't model, and decodes
+// the subsonic-response document into v.
+func (s *subsonicMediaProvider) getRaw(endpoint string, params map[string]string, v any) error {
+	values := url.Values{}
+	for k, val := range params {
+		values.Add(k, val)
+	}
+	return s.getRawValues(endpoint, values, v)
+}
+
+// getRawValues is like getRaw, but supports repeated parameters.
+func (s *subsonicMediaProvider) getRawValues(endpoint string, values url.Values, v any) error {
+	resp, err := s.client.Request("GET", endpoint, values)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var parsed subsonic.Response
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	if parsed.Error != nil {
+		return responseError(parsed.Error)
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// responseError formats an error reported in a subsonic-response
+// the same way as the go-subsonic client does.
+func responseError(e *subsonic.Error) error {
+	return apiErr(fmt.Errorf("Error #%d: %s", e.Code, e.Message))
+}
+
+// parseDateTime parses an xsd:dateTime, which servers may send
+// without a time zone. Returns the zero time if it can't be parsed.
+func parseDateTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,30 @@
+package subsonic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/backend/mediaprovider/helpers"
+)
+
+// retryGet runs fn, an idempotent read-only request, retrying transient
+// failures according to the configured retry policy. Mutating requests
+// (scrobbles, stars, playlist edits) must not use it, to avoid
+// applying them twice if a response is lost after the server handled it.
+func retryGet[T any](ctx context.Context, s *subsonicMediaProvider, fn func() (T, error)) (T, error) {
+	return helpers.Retry(ctx, s.options.Retry, isTransientErr, func() (T, error) {
+		return apiResult(fn())
+	})
+}
+
+// isTransientErr reports whether err may succeed if retried. Errors reported
+// by the Subsonic API itself (e.g. not found, not authorized) are definitive;
+// network errors and unparseable responses (e.g. 5xx error pages) are not.
+func isTransientErr(err error) bool {
+	if mediaprovider.IsAuthError(err) || errors.Is(err, mediaprovider.ErrNotAuthorized) {
+		return false
+	}
+	return !strings.HasPrefix(err.Error(), "Error #")
+}
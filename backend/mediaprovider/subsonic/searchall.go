@@ -1,6 +1,7 @@
 package subsonic
 
 import (
+	"context"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,6 +13,14 @@ import (
 	"github.com/supersonic-app/go-subsonic/subsonic"
 )
 
+// The go-subsonic client doesn't accept a context, so a canceled
+// search returns immediately, abandoning its in-flight requests.
+func (s *subsonicMediaProvider) SearchAllContext(ctx context.Context, searchQuery string, maxResults int) ([]*mediaprovider.SearchResult, error) {
+	return helpers.DoContext(ctx, func() ([]*mediaprovider.SearchResult, error) {
+		return s.SearchAll(searchQuery, maxResults)
+	})
+}
+
 func (s *subsonicMediaProvider) SearchAll(searchQuery string, maxResults int) ([]*mediaprovider.SearchResult, error) {
 	var wg sync.WaitGroup
 	var err error // only set by Search3
@@ -23,11 +32,11 @@ func (s *subsonicMediaProvider) SearchAll(searchQuery string, maxResults int) ([
 	wg.Add(1)
 	go func() {
 		count := strconv.Itoa(maxResults / 3)
-		res, e := s.client.Search3(searchQuery, map[string]string{
+		res, e := apiResult(s.client.Search3(searchQuery, map[string]string{
 			"artistCount": count,
 			"albumCount":  count,
 			"songCount":   count,
-		})
+		}))
 		if e != nil {
 			err = e
 		} else {
@@ -41,7 +50,7 @@ func (s *subsonicMediaProvider) SearchAll(searchQuery string, maxResults int) ([
 
 	wg.Add(1)
 	go func() {
-		p, e := s.client.GetPlaylists(nil)
+		p, e := apiResult(s.client.GetPlaylists(nil))
 		if e == nil {
 			playlists = sharedutil.FilterSlice(p, func(p *subsonic.Playlist) bool {
 				return helpers.AllTermsMatch(strings.ToLower(sanitize.Accents(p.Name)), queryLowerWords)
@@ -52,7 +61,7 @@ func (s *subsonicMediaProvider) SearchAll(searchQuery string, maxResults int) ([
 
 	wg.Add(1)
 	go func() {
-		g, e := s.client.GetGenres()
+		g, e := apiResult(s.client.GetGenres())
 		if e == nil {
 			genres = sharedutil.FilterSlice(g, func(g *subsonic.Genre) bool {
 				return helpers.AllTermsMatch(strings.ToLower(sanitize.Accents(g.Name)), queryLowerWords)
@@ -85,6 +94,40 @@ func (s *subsonicMediaProvider) SearchAll(searchQuery string, maxResults int) ([
 	return results, nil
 }
 
+// SupportsPagedSearch interface
+var _ mediaprovider.SupportsPagedSearch = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) SearchAllPaged(searchQuery string, kind mediaprovider.ContentType, offset, count int) ([]*mediaprovider.SearchResult, error) {
+	var prefix string
+	switch kind {
+	case mediaprovider.ContentTypeArtist:
+		prefix = "artist"
+	case mediaprovider.ContentTypeAlbum:
+		prefix = "album"
+	case mediaprovider.ContentTypeTrack:
+		prefix = "song"
+	default:
+		return nil, mediaprovider.ErrNotSupported
+	}
+	params := map[string]string{
+		"artistCount": "0",
+		"albumCount":  "0",
+		"songCount":   "0",
+	}
+	params[prefix+"Count"] = strconv.Itoa(count)
+	params[prefix+"Offset"] = strconv.Itoa(offset)
+	res, err := retryGet(context.Background(), s, func() (*subsonic.SearchResult3, error) {
+		return s.client.Search3(searchQuery, params)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return mergeResults(res, nil, nil, nil), nil
+}
+
 func mergeResults(
 	searchResult *subsonic.SearchResult3,
 	matchingPlaylists []*subsonic.Playlist,
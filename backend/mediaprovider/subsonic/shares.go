@@ -0,0 +1,66 @@
+package subsonic
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsShareManagement = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) CanManageShares() bool {
+	user, err := s.getCurrentUser()
+	return err == nil && user != nil && user.ShareRole
+}
+
+func (s *subsonicMediaProvider) GetShares() ([]*mediaprovider.Share, error) {
+	shares, err := apiResult(s.client.GetShares())
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(shares, toShare), nil
+}
+
+func (s *subsonicMediaProvider) CreateShare(ids []string, description string, expires time.Time) (*mediaprovider.Share, error) {
+	// the subsonic library sends a single item ID per createShare request
+	if len(ids) != 1 {
+		return nil, &mediaprovider.ValidationError{Field: "ids", Reason: "exactly one item can be shared per link"}
+	}
+	share, err := apiResult(s.client.CreateShare(ids[0], shareParams(description, expires)))
+	if err != nil {
+		return nil, err
+	}
+	return toShare(share), nil
+}
+
+func (s *subsonicMediaProvider) UpdateShare(id string, description string, expires time.Time) error {
+	return apiErr(s.client.UpdateShare(id, shareParams(description, expires)))
+}
+
+func (s *subsonicMediaProvider) DeleteShare(id string) error {
+	return apiErr(s.client.DeleteShare(id))
+}
+
+func shareParams(description string, expires time.Time) map[string]string {
+	params := map[string]string{}
+	if description != "" {
+		params["description"] = description
+	}
+	if !expires.IsZero() {
+		params["expires"] = strconv.FormatInt(expires.UnixMilli(), 10)
+	}
+	return params
+}
+
+func toShare(sh *subsonic.Share) *mediaprovider.Share {
+	return &mediaprovider.Share{
+		ID:          sh.ID,
+		URL:         sh.Url,
+		Description: sh.Description,
+		Expires:     sh.Expires,
+		VisitCount:  sh.VisitCount,
+	}
+}
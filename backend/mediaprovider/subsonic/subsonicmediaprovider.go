@@ -1,10 +1,12 @@
 package subsonic
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"image"
 	"io"
-	"math"
 	"net/url"
 	"slices"
 	"strconv"
@@ -19,40 +21,261 @@ import (
 )
 
 const (
-	playlistCacheValidDurationSeconds = 60
-	cacheValidDurationSeconds         = 120 // genres and radios aren't expected to change as much
+	defaultPlaylistCacheTTL   = 60 * time.Second
+	defaultGenreCacheTTL      = 120 * time.Second
+	cacheValidDurationSeconds = 120 // radios aren't expected to change as much
+
+	// max number of random albums to request in GetRandomAlbum
+	// before giving up on finding one that matches the filter
+	randomAlbumMaxAttempts = 10
+
+	// page size (the server max) of the starred album list
+	// requests made when VerifyFavoritesPerType is set
+	favoritesVerifyPageSize = 500
+
+	// factor by which GetSimilarTracksDiverse over-fetches similar
+	// songs to leave enough after filtering out the seed's album
+	similarDiverseOverfetch = 3
 )
 
+// Options configures optional behavior of the Subsonic media provider.
+// The zero value gives the default behavior.
+type Options struct {
+	// If true, GetPlaylist includes placeholder tracks (with Missing set)
+	// for playlist entries that are no longer available on the server,
+	// rather than dropping them.
+	KeepMissingTracks bool
+
+	// Max number of concurrent requests issued by batch operations
+	// (e.g. SetRating). 0 means helpers.DefaultBatchConcurrency.
+	BatchConcurrency int
+
+	// Length of the random salt used for token auth in stream URLs.
+	// 0 keeps the client library's salt, generated at login.
+	// This and AuthTokenTTL apply only to providers created by
+	// SubsonicServer.MediaProvider, which generates the tokens at login.
+	AuthSaltLength int
+
+	// How often the salt and token used in stream URLs are rotated.
+	// Since the password isn't kept after login, rotation cycles through
+	// a fixed set of salts and tokens generated at login.
+	// 0 with AuthSaltLength unset keeps the client library's token, generated at login;
+	// 0 with AuthSaltLength set uses a single token generated at login.
+	AuthTokenTTL time.Duration
+
+	// If true, GetCoverArtOrPlaceholder returns a generated placeholder image
+	// for an empty cover art ID rather than requesting it from the server.
+	PlaceholderCoverArt bool
+
+	// Sizes (px) that GetCoverArtBestFit snaps requested sizes up to.
+	// nil means helpers.DefaultCoverSizePresets.
+	CoverSizePresets []int
+
+	// Some servers don't reliably report all types of favorites from
+	// getStarred2. If true, GetFavorites cross-checks an empty album or
+	// artist category with a targeted query, at the cost of extra requests.
+	VerifyFavoritesPerType bool
+
+	// If true, concurrent identical GetAlbum, GetAlbumInfo, GetArtist
+	// and GetCoverArt calls share a single in-flight server request.
+	// Callers then share the returned value and must not modify it.
+	CoalesceRequests bool
+
+	// Policy for retrying idempotent read requests that fail with a
+	// transient error, such as a timeout or server error page.
+	// The zero value disables retries.
+	Retry helpers.RetryPolicy
+
+	// Username of the chat user whose messages GetServerMessage reports as
+	// server announcements, since Subsonic has no dedicated announcement
+	// field. If empty, GetServerMessage always returns the empty string.
+	AnnouncementSender string
+}
+
 type subsonicMediaProvider struct {
 	client          *subsonic.Client
+	options         Options
 	prefetchCoverCB func(coverArtID string)
 
 	genresCached   []*mediaprovider.Genre
 	genresCachedAt int64 // unix
+	genresCacheTTL time.Duration
 
 	playlistsCached   []*mediaprovider.Playlist
 	playlistsCachedAt int64 // unix
+	playlistsCacheTTL time.Duration
 
 	radiosCached   []*mediaprovider.RadioStation
 	radiosCachedAt int64 // unix
+
+	playStatsLock           sync.Mutex
+	artistPlayStatsCached   map[string]artistPlayStats // keyed by artist ID
+	genrePlayCountsCached   map[string]int             // keyed by genre name
+	artistPlayStatsCachedAt int64                      // unix
+
+	genresByListeningCached   []*mediaprovider.Genre
+	genresByListeningCachedAt int64 // unix
+
+	extensionsOnce sync.Once
+	extensions     map[string][]int // OpenSubsonic extension name -> supported versions
+
+	activeMusicFolderID string
+
+	streamAuth        streamAuth
+	streamURLRewriter func(u *url.URL) *url.URL
+
+	clockSkewOnce sync.Once
+	clockSkew     time.Duration
+
+	librarySectionsLock   sync.Mutex
+	librarySectionsCached []*mediaprovider.LibrarySection
+
+	userLock     sync.Mutex
+	userCached   *subsonic.User
+	userCachedAt int64 // unix
+
+	albumCalls     helpers.Coalescer[*mediaprovider.AlbumWithTracks]
+	albumInfoCalls helpers.Coalescer[*mediaprovider.AlbumInfo]
+	artistCalls    helpers.Coalescer[*mediaprovider.ArtistWithAlbums]
+	coverArtCalls  helpers.Coalescer[image.Image]
 }
 
 func SubsonicMediaProvider(subsonicClient *subsonic.Client) mediaprovider.MediaProvider {
-	return &subsonicMediaProvider{client: subsonicClient}
+	return SubsonicMediaProviderWithOptions(subsonicClient, Options{})
+}
+
+func SubsonicMediaProviderWithOptions(subsonicClient *subsonic.Client, options Options) mediaprovider.MediaProvider {
+	return &subsonicMediaProvider{
+		client:            subsonicClient,
+		options:           options,
+		genresCacheTTL:    defaultGenreCacheTTL,
+		playlistsCacheTTL: defaultPlaylistCacheTTL,
+	}
+}
+
+// SupportsCacheTTL interface
+var _ mediaprovider.SupportsCacheTTL = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) SetCacheTTL(d time.Duration) {
+	s.genresCacheTTL = d
+	s.playlistsCacheTTL = d
+}
+
+// SupportsCacheInvalidation interface
+var _ mediaprovider.SupportsCacheInvalidation = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) InvalidateCaches() {
+	s.genresCached = nil
+	s.genresByListeningCached = nil
+	s.playlistsCached = nil
+	s.radiosCached = nil
+
+	s.playStatsLock.Lock()
+	s.artistPlayStatsCached = nil
+	s.playStatsLock.Unlock()
+
+	s.userLock.Lock()
+	s.userCached = nil
+	s.userLock.Unlock()
+
+	s.librarySectionsLock.Lock()
+	s.librarySectionsCached = nil
+	s.librarySectionsLock.Unlock()
+}
+
+// Returns true if a cache entry stored at cachedAt (unix) is still within ttl.
+func cacheValid(cachedAt int64, ttl time.Duration) bool {
+	return time.Now().Unix()-cachedAt < int64(ttl.Seconds())
+}
+
+// OpenSubsonicExtensions returns the OpenSubsonic extensions advertised by
+// the server, mapped to their supported versions. The list is fetched once
+// per session; servers without OpenSubsonic support report no extensions.
+func (s *subsonicMediaProvider) OpenSubsonicExtensions() map[string][]int {
+	s.extensionsOnce.Do(func() {
+		s.extensions = make(map[string][]int)
+		ext, err := apiResult(s.client.GetOpenSubsonicExtensions())
+		if err != nil {
+			return
+		}
+		for _, e := range ext {
+			s.extensions[e.Name] = e.Versions
+		}
+	})
+	return s.extensions
+}
+
+// HasExtension returns true if the server supports the given
+// OpenSubsonic extension at version minVersion or later.
+func (s *subsonicMediaProvider) HasExtension(name string, minVersion int) bool {
+	versions, ok := s.OpenSubsonicExtensions()[name]
+	return ok && slices.ContainsFunc(versions, func(v int) bool { return v >= minVersion })
+}
+
+// hasExtension returns true if the server supports any version of the extension.
+// Safe for concurrent use; the extension list is only fetched once.
+func (s *subsonicMediaProvider) hasExtension(name string) bool {
+	_, ok := s.OpenSubsonicExtensions()[name]
+	return ok
 }
 
 func (s *subsonicMediaProvider) SetPrefetchCoverCallback(cb func(coverArtID string)) {
 	s.prefetchCoverCB = cb
 }
 
+// SupportsCoverArtPrefetch interface
+var _ mediaprovider.SupportsCoverArtPrefetch = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) PrefetchCoverArt(ids []string) {
+	cb := s.prefetchCoverCB
+	if cb == nil {
+		return
+	}
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	go helpers.RunConcurrently(context.Background(), len(unique), s.BatchConcurrency(), func(i int) {
+		cb(unique[i])
+	})
+}
+
 func (s *subsonicMediaProvider) CreatePlaylist(name string, trackIDs []string) error {
 	s.playlistsCached = nil
-	return s.client.CreatePlaylistWithTracks(trackIDs, map[string]string{"name": name})
+	return apiErr(s.client.CreatePlaylistWithTracks(trackIDs, map[string]string{"name": name}))
+}
+
+// SupportsCreatePlaylistWithID interface
+var _ mediaprovider.SupportsCreatePlaylistWithID = (*subsonicMediaProvider)(nil)
+
+// Servers implementing API 1.14+ return the new playlist from createPlaylist,
+// which the go-subsonic client discards, so the response is decoded into this.
+type createPlaylistResponse struct {
+	Playlist struct {
+		ID string `xml:"id,attr"`
+	} `xml:"playlist"`
+}
+
+func (s *subsonicMediaProvider) CreatePlaylistWithID(name string, trackIDs []string) (string, error) {
+	s.playlistsCached = nil
+	values := url.Values{"name": {name}}
+	for _, id := range trackIDs {
+		values.Add("songId", id)
+	}
+	var resp createPlaylistResponse
+	if err := s.getRawValues("createPlaylist", values, &resp); err != nil {
+		return "", err
+	}
+	return resp.Playlist.ID, nil
 }
 
 func (s *subsonicMediaProvider) DeletePlaylist(id string) error {
 	s.playlistsCached = nil
-	return s.client.DeletePlaylist(id)
+	return apiErr(s.client.DeletePlaylist(id))
 }
 
 func (s *subsonicMediaProvider) CanMakePublicPlaylist() bool {
@@ -61,33 +284,75 @@ func (s *subsonicMediaProvider) CanMakePublicPlaylist() bool {
 
 func (s *subsonicMediaProvider) EditPlaylist(id, name, description string, public bool) error {
 	s.playlistsCached = nil
-	return s.client.UpdatePlaylist(id, map[string]string{
+	return apiErr(s.client.UpdatePlaylist(id, map[string]string{
 		"name":    name,
 		"comment": description,
 		"public":  strconv.FormatBool(public),
-	})
+	}))
 }
 
 func (s *subsonicMediaProvider) AddPlaylistTracks(id string, trackIDsToAdd []string) error {
 	s.playlistsCached = nil
-	return s.client.UpdatePlaylistTracks(id, trackIDsToAdd, nil)
+	return apiErr(s.client.UpdatePlaylistTracks(id, trackIDsToAdd, nil))
 }
 
 func (s *subsonicMediaProvider) RemovePlaylistTracks(id string, removeIdxs []int) error {
 	s.playlistsCached = nil
-	return s.client.UpdatePlaylistTracks(id, nil, removeIdxs)
+	if !s.options.KeepMissingTracks {
+		// GetPlaylist drops unavailable entries, so the indexes of the tracks
+		// it returns may not match the positions in the server's playlist
+		pl, err := apiResult(s.client.GetPlaylist(id))
+		if err != nil {
+			return err
+		}
+		positions := s.playlistTrackPositions(pl.Entry)
+		serverIdxs := make([]int, 0, len(removeIdxs))
+		for _, idx := range removeIdxs {
+			if idx < 0 || idx >= len(positions) {
+				return fmt.Errorf("playlist track index %d out of range", idx)
+			}
+			serverIdxs = append(serverIdxs, positions[idx])
+		}
+		removeIdxs = serverIdxs
+	}
+	return apiErr(s.client.UpdatePlaylistTracks(id, nil, removeIdxs))
+}
+
+// playlistTrackPositions returns the position in entries
+// of each of the tracks GetPlaylist maps them to.
+func (s *subsonicMediaProvider) playlistTrackPositions(entries []*subsonic.Child) []int {
+	positions := make([]int, 0, len(entries))
+	for i, ch := range entries {
+		if _, ok := s.toPlaylistTrack(ch); ok {
+			positions = append(positions, i)
+		}
+	}
+	return positions
 }
 
 func (s *subsonicMediaProvider) GetTrack(trackID string) (*mediaprovider.Track, error) {
-	tr, err := s.client.GetSong(trackID)
+	tr, err := retryGet(context.Background(), s, func() (*subsonic.Child, error) {
+		return s.client.GetSong(trackID)
+	})
 	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, fmt.Errorf("%w: %s", mediaprovider.ErrNotFound, err.Error())
+		}
 		return nil, err
 	}
 	return toTrack(tr), nil
 }
 
 func (s *subsonicMediaProvider) GetAlbum(albumID string) (*mediaprovider.AlbumWithTracks, error) {
-	al, err := s.client.GetAlbum(albumID)
+	return coalesce(s, &s.albumCalls, albumID, func() (*mediaprovider.AlbumWithTracks, error) {
+		return s.getAlbum(albumID)
+	})
+}
+
+func (s *subsonicMediaProvider) getAlbum(albumID string) (*mediaprovider.AlbumWithTracks, error) {
+	al, err := retryGet(context.Background(), s, func() (*subsonic.AlbumID3, error) {
+		return s.client.GetAlbum(albumID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -95,11 +360,26 @@ func (s *subsonicMediaProvider) GetAlbum(albumID string) (*mediaprovider.AlbumWi
 		Tracks: sharedutil.MapSlice(al.Song, toTrack),
 	}
 	fillAlbum(al, &album.Album)
+	// AlbumID3 doesn't carry the album's last played time,
+	// so derive it from its tracks
+	for _, tr := range album.Tracks {
+		if tr.LastPlayed.After(album.LastPlayed) {
+			album.LastPlayed = tr.LastPlayed
+		}
+	}
 	return album, nil
 }
 
 func (s *subsonicMediaProvider) GetAlbumInfo(albumID string) (*mediaprovider.AlbumInfo, error) {
-	al, err := s.client.GetAlbumInfo(albumID)
+	return coalesce(s, &s.albumInfoCalls, albumID, func() (*mediaprovider.AlbumInfo, error) {
+		return s.getAlbumInfo(albumID)
+	})
+}
+
+func (s *subsonicMediaProvider) getAlbumInfo(albumID string) (*mediaprovider.AlbumInfo, error) {
+	al, err := retryGet(context.Background(), s, func() (*subsonic.AlbumInfo, error) {
+		return s.client.GetAlbumInfo(albumID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +392,15 @@ func (s *subsonicMediaProvider) GetAlbumInfo(albumID string) (*mediaprovider.Alb
 }
 
 func (s *subsonicMediaProvider) GetArtist(artistID string) (*mediaprovider.ArtistWithAlbums, error) {
-	ar, err := s.client.GetArtist(artistID)
+	return coalesce(s, &s.artistCalls, artistID, func() (*mediaprovider.ArtistWithAlbums, error) {
+		return s.getArtist(artistID)
+	})
+}
+
+func (s *subsonicMediaProvider) getArtist(artistID string) (*mediaprovider.ArtistWithAlbums, error) {
+	ar, err := retryGet(context.Background(), s, func() (*subsonic.ArtistID3, error) {
+		return s.client.GetArtist(artistID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +421,9 @@ func (s *subsonicMediaProvider) GetArtistTracks(artistID string) ([]*mediaprovid
 }
 
 func (s *subsonicMediaProvider) GetArtistInfo(artistID string) (*mediaprovider.ArtistInfo, error) {
-	info, err := s.client.GetArtistInfo2(artistID, map[string]string{})
+	info, err := retryGet(context.Background(), s, func() (*subsonic.ArtistInfo2, error) {
+		return s.client.GetArtistInfo2(artistID, map[string]string{})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -149,31 +439,110 @@ func (s *subsonicMediaProvider) GetArtistInfo(artistID string) (*mediaprovider.A
 }
 
 func (s *subsonicMediaProvider) GetCoverArt(id string, size int) (image.Image, error) {
-	params := map[string]string{}
-	if size > 0 {
-		params["size"] = strconv.Itoa(size)
+	key := id + "/" + strconv.Itoa(size)
+	return coalesce(s, &s.coverArtCalls, key, func() (image.Image, error) {
+		data, _, err := s.GetCoverArtBytes(id, size)
+		if err != nil {
+			return nil, err
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		return img, err
+	})
+}
+
+// coalesce runs fn through c if request coalescing is enabled,
+// and directly otherwise.
+func coalesce[T any](s *subsonicMediaProvider, c *helpers.Coalescer[T], key string, fn func() (T, error)) (T, error) {
+	if !s.options.CoalesceRequests {
+		return fn()
+	}
+	return c.Do(key, fn)
+}
+
+// SupportsPlaceholderCoverArt interface
+var _ mediaprovider.SupportsPlaceholderCoverArt = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetCoverArtOrPlaceholder(id, seed string, size int) (image.Image, error) {
+	if id == "" && s.options.PlaceholderCoverArt {
+		return helpers.GeneratePlaceholderCover(seed, size), nil
+	}
+	return s.GetCoverArt(id, size)
+}
+
+// SupportsCoverArtBestFit interface
+var _ mediaprovider.SupportsCoverArtBestFit = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetCoverArtBestFit(id string, targetPx int) (image.Image, error) {
+	presets := s.options.CoverSizePresets
+	if len(presets) == 0 {
+		presets = helpers.DefaultCoverSizePresets
 	}
-	return s.client.GetCoverArt(id, params)
+	return s.GetCoverArt(id, helpers.SnapCoverSize(targetPx, presets))
 }
 
 func (s *subsonicMediaProvider) GetFavorites() (mediaprovider.Favorites, error) {
-	fav, err := s.client.GetStarred2(map[string]string{})
+	fav, err := apiResult(s.client.GetStarred2(map[string]string{}))
 	if err != nil {
 		return mediaprovider.Favorites{}, err
 	}
-	return mediaprovider.Favorites{
+	favs := mediaprovider.Favorites{
 		Albums:  sharedutil.MapSlice(fav.Album, toAlbum),
 		Artists: sharedutil.MapSlice(fav.Artist, toArtistFromID3),
 		Tracks:  sharedutil.MapSlice(fav.Song, toTrack),
-	}, nil
+	}
+	if s.options.VerifyFavoritesPerType {
+		if err := s.verifyEmptyFavorites(&favs); err != nil {
+			return mediaprovider.Favorites{}, err
+		}
+	}
+	return favs, nil
+}
+
+// verifyEmptyFavorites fills in any empty album or artist category of favs
+// from per-type ID3 queries: the starred album list, and the starred artists
+// in the artist index. There is no per-type ID3 query for starred tracks.
+func (s *subsonicMediaProvider) verifyEmptyFavorites(favs *mediaprovider.Favorites) error {
+	if len(favs.Albums) == 0 {
+		for offset := 0; ; offset += favoritesVerifyPageSize {
+			albums, err := s.getAlbumList2("starred", map[string]string{
+				"size":   strconv.Itoa(favoritesVerifyPageSize),
+				"offset": strconv.Itoa(offset),
+			})
+			if err != nil {
+				return err
+			}
+			favs.Albums = append(favs.Albums, albums...)
+			if len(albums) < favoritesVerifyPageSize {
+				break
+			}
+		}
+	}
+	if len(favs.Artists) == 0 {
+		idxs, err := apiResult(s.client.GetArtists(map[string]string{}))
+		if err != nil {
+			return err
+		}
+		if idxs != nil {
+			for _, idx := range idxs.Index {
+				for _, ar := range idx.Artist {
+					if !ar.Starred.IsZero() {
+						favs.Artists = append(favs.Artists, toArtistFromID3(ar))
+					}
+				}
+			}
+		}
+	}
+	return nil
 }
 
 func (s *subsonicMediaProvider) GetGenres() ([]*mediaprovider.Genre, error) {
-	if s.genresCached != nil && time.Now().Unix()-s.genresCachedAt < cacheValidDurationSeconds {
+	if s.genresCached != nil && cacheValid(s.genresCachedAt, s.genresCacheTTL) {
 		return s.genresCached, nil
 	}
 
-	g, err := s.client.GetGenres()
+	g, err := retryGet(context.Background(), s, func() ([]*subsonic.Genre, error) {
+		return s.client.GetGenres()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -188,24 +557,71 @@ func (s *subsonicMediaProvider) GetGenres() ([]*mediaprovider.Genre, error) {
 	return s.genresCached, nil
 }
 
+// SupportsGenresByListening interface
+var _ mediaprovider.SupportsGenresByListening = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetGenresByListening() ([]*mediaprovider.Genre, error) {
+	if s.genresByListeningCached != nil && cacheValid(s.genresByListeningCachedAt, s.genresCacheTTL) {
+		return s.genresByListeningCached, nil
+	}
+
+	genres, err := s.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+	// play counts are only derivable from the most played albums,
+	// so order by album count among genres with no known plays
+	playCounts := s.getGenrePlayCounts()
+	sorted := slices.Clone(genres)
+	slices.SortStableFunc(sorted, func(a, b *mediaprovider.Genre) int {
+		if c := playCounts[b.Name] - playCounts[a.Name]; c != 0 {
+			return c
+		}
+		return b.AlbumCount - a.AlbumCount
+	})
+	s.genresByListeningCached = sorted
+	s.genresByListeningCachedAt = time.Now().Unix()
+	return sorted, nil
+}
+
 func (s *subsonicMediaProvider) GetPlaylist(playlistID string) (*mediaprovider.PlaylistWithTracks, error) {
-	pl, err := s.client.GetPlaylist(playlistID)
+	pl, err := retryGet(context.Background(), s, func() (*subsonic.Playlist, error) {
+		return s.client.GetPlaylist(playlistID)
+	})
 	if err != nil {
 		return nil, err
 	}
 	playlist := &mediaprovider.PlaylistWithTracks{
-		Tracks: sharedutil.MapSlice(pl.Entry, toTrack),
+		Tracks: sharedutil.FilterMapSlice(pl.Entry, s.toPlaylistTrack),
 	}
 	fillPlaylist(pl, &playlist.Playlist)
 	return playlist, nil
 }
 
+// Maps a playlist entry to a track. Entries the server can no longer
+// resolve (returned without any metadata) are dropped, or mapped to a
+// placeholder track if the KeepMissingTracks option is set.
+func (s *subsonicMediaProvider) toPlaylistTrack(ch *subsonic.Child) (*mediaprovider.Track, bool) {
+	if ch == nil {
+		return nil, false
+	}
+	if ch.Title == "" && ch.Duration == 0 && ch.Path == "" {
+		if s.options.KeepMissingTracks && ch.ID != "" {
+			return &mediaprovider.Track{ID: ch.ID, Missing: true}, true
+		}
+		return nil, false
+	}
+	return toTrack(ch), true
+}
+
 func (s *subsonicMediaProvider) GetPlaylists() ([]*mediaprovider.Playlist, error) {
-	if s.playlistsCached != nil && time.Now().Unix()-s.playlistsCachedAt < playlistCacheValidDurationSeconds {
+	if s.playlistsCached != nil && cacheValid(s.playlistsCachedAt, s.playlistsCacheTTL) {
 		return s.playlistsCached, nil
 	}
 
-	pl, err := s.client.GetPlaylists(map[string]string{})
+	pl, err := retryGet(context.Background(), s, func() ([]*subsonic.Playlist, error) {
+		return s.client.GetPlaylists(map[string]string{})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -215,19 +631,59 @@ func (s *subsonicMediaProvider) GetPlaylists() ([]*mediaprovider.Playlist, error
 }
 
 func (s *subsonicMediaProvider) GetRandomTracks(genreName string, count int) ([]*mediaprovider.Track, error) {
-	opts := map[string]string{"size": strconv.Itoa(count)}
-	if genreName != "" {
-		opts["genre"] = genreName
+	return s.getRandomSongs(genreName, count, s.activeMusicFolderID)
+}
+
+func (s *subsonicMediaProvider) GetSimilarTracks(artistID string, count int) ([]*mediaprovider.Track, error) {
+	return s.getSimilarSongs(artistID, count)
+}
+
+// SupportsDiverseSimilarTracks interface
+var _ mediaprovider.SupportsDiverseSimilarTracks = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetSimilarTracksDiverse(trackID string, count int) ([]*mediaprovider.Track, error) {
+	seed, err := s.GetTrack(trackID)
+	if err != nil {
+		return nil, err
 	}
-	tr, err := s.client.GetRandomSongs(opts)
+	// getSimilarSongs2 is seeded by an artist; getSimilarSongs accepts a track
+	tr, err := apiResult(s.client.GetSimilarSongs(trackID, map[string]string{"count": strconv.Itoa(count * similarDiverseOverfetch)}))
 	if err != nil {
 		return nil, err
 	}
-	return sharedutil.MapSlice(tr, toTrack), nil
+	similar := sharedutil.MapSlice(tr, toTrack)
+
+	// prefer tracks by other artists, falling back to other
+	// albums by the seed's artist if there aren't enough
+	var otherArtist, sameArtist []*mediaprovider.Track
+	for _, tr := range similar {
+		if tr.ID == seed.ID || (seed.AlbumID != "" && tr.AlbumID == seed.AlbumID) {
+			continue
+		}
+		if sharesArtist(tr, seed) {
+			sameArtist = append(sameArtist, tr)
+		} else {
+			otherArtist = append(otherArtist, tr)
+		}
+	}
+	result := otherArtist
+	if len(result) < count {
+		result = append(result, sameArtist[:min(len(sameArtist), count-len(result))]...)
+	}
+	return result[:min(len(result), count)], nil
 }
 
-func (s *subsonicMediaProvider) GetSimilarTracks(artistID string, count int) ([]*mediaprovider.Track, error) {
-	tr, err := s.client.GetSimilarSongs2(artistID, map[string]string{"count": strconv.Itoa(count)})
+func sharesArtist(a, b *mediaprovider.Track) bool {
+	for _, id := range a.ArtistIDs {
+		if slices.Contains(b.ArtistIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *subsonicMediaProvider) getSimilarSongs(id string, count int) ([]*mediaprovider.Track, error) {
+	tr, err := apiResult(s.client.GetSimilarSongs2(id, map[string]string{"count": strconv.Itoa(count)}))
 	if err != nil {
 		return nil, err
 	}
@@ -235,23 +691,148 @@ func (s *subsonicMediaProvider) GetSimilarTracks(artistID string, count int) ([]
 }
 
 func (s *subsonicMediaProvider) GetStreamURL(trackID string, forceRaw bool) (string, error) {
+	return s.GetStreamURLWithOptions(trackID, mediaprovider.StreamOptions{ForceRaw: forceRaw})
+}
+
+// SupportsStreamOptions interface
+var _ mediaprovider.SupportsStreamOptions = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetStreamURLWithOptions(trackID string, opts mediaprovider.StreamOptions) (string, error) {
 	m := make(map[string]string)
-	if forceRaw {
+	if opts.ForceRaw {
 		m["format"] = "raw"
+	} else {
+		if opts.Format != "" {
+			m["format"] = opts.Format
+		}
+		if opts.MaxBitRate > 0 {
+			m["maxBitRate"] = strconv.Itoa(opts.MaxBitRate)
+		}
 	}
-	u, err := s.client.GetStreamURL(trackID, m)
+	u, err := s.buildStreamURL(trackID, m)
 	if err != nil {
 		return "", err
 	}
 	return u.String(), nil
 }
 
+// SupportsStreamURLBoth interface
+var _ mediaprovider.SupportsStreamURLBoth = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetStreamURLBoth(trackID string) (string, string, error) {
+	u, err := s.buildStreamURL(trackID, map[string]string{})
+	if err != nil {
+		return "", "", err
+	}
+	// derive the raw URL from the transcoded one so both share the same auth token
+	raw := *u
+	q := raw.Query()
+	q.Set("format", "raw")
+	q.Del("maxBitRate")
+	raw.RawQuery = q.Encode()
+	return raw.String(), u.String(), nil
+}
+
+// SupportsAlbumStreamURLs interface
+var _ mediaprovider.SupportsAlbumStreamURLs = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetAlbumWithStreamURLs(ctx context.Context, albumID string, forceRaw bool) (*mediaprovider.AlbumWithTracks, []string, error) {
+	album, err := s.GetAlbum(albumID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(album.Tracks) == 0 {
+		return album, nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	m := make(map[string]string)
+	if forceRaw {
+		m["format"] = "raw"
+	}
+	base, err := s.buildStreamURL(album.Tracks[0].ID, m)
+	if err != nil {
+		return nil, nil, err
+	}
+	// build the rest from the first URL so all share the same auth token
+	urls := make([]string, len(album.Tracks))
+	q := base.Query()
+	for i, tr := range album.Tracks {
+		q.Set("id", tr.ID)
+		u := *base
+		u.RawQuery = q.Encode()
+		urls[i] = u.String()
+	}
+	return album, urls, nil
+}
+
+// buildStreamURL builds the stream URL for the track with the given
+// query params. Unless the caller requests a specific bit rate or the raw
+// format, the user's server-configured max bit rate is applied.
+// The stream URL rewriter, if set, is applied to the signed URL.
+func (s *subsonicMediaProvider) buildStreamURL(trackID string, params map[string]string) (*url.URL, error) {
+	if _, ok := params["maxBitRate"]; !ok && params["format"] != "raw" {
+		if br, err := s.GetMaxBitRate(); err == nil && br > 0 {
+			params["maxBitRate"] = strconv.Itoa(br)
+		}
+	}
+	u, err := s.client.GetStreamURL(trackID, params)
+	if err != nil {
+		return nil, err
+	}
+	s.signStreamURL(u)
+	if s.streamURLRewriter != nil {
+		if rewritten := s.streamURLRewriter(u); rewritten != nil {
+			u = rewritten
+		}
+	}
+	return u, nil
+}
+
+// SupportsStreamURLRewriting interface
+var _ mediaprovider.SupportsStreamURLRewriting = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) SetStreamURLRewriter(rewriter func(u *url.URL) *url.URL) {
+	s.streamURLRewriter = rewriter
+}
+
+// GetMaxBitRate returns the max streaming bit rate (kbps) configured for
+// the logged in user on the server, or 0 if it is unlimited or unknown.
+func (s *subsonicMediaProvider) GetMaxBitRate() (int, error) {
+	user, err := s.getCurrentUser()
+	if err != nil {
+		return 0, err
+	}
+	if user == nil {
+		return 0, nil
+	}
+	return user.MaxBitRate, nil
+}
+
+func (s *subsonicMediaProvider) getCurrentUser() (*subsonic.User, error) {
+	s.userLock.Lock()
+	defer s.userLock.Unlock()
+	if s.userCached != nil && time.Now().Unix()-s.userCachedAt < cacheValidDurationSeconds {
+		return s.userCached, nil
+	}
+
+	user, err := apiResult(s.client.GetUser(s.client.User))
+	if err != nil {
+		return nil, err
+	}
+	s.userCached = user
+	s.userCachedAt = time.Now().Unix()
+	return user, nil
+}
+
 func (s *subsonicMediaProvider) GetTopTracks(artist mediaprovider.Artist, count int) ([]*mediaprovider.Track, error) {
 	params := map[string]string{}
 	if count > 0 {
 		params["count"] = strconv.Itoa(count)
 	}
-	tr, err := s.client.GetTopSongs(artist.Name, params)
+	tr, err := apiResult(s.client.GetTopSongs(artist.Name, params))
 	if err != nil {
 		return nil, err
 	}
@@ -263,24 +844,35 @@ func (s *subsonicMediaProvider) GetTopTracks(artist mediaprovider.Artist, count
 
 func (s *subsonicMediaProvider) ReplacePlaylistTracks(playlistID string, trackIDs []string) error {
 	s.playlistsCached = nil
-	return s.client.CreatePlaylistWithTracks(trackIDs, map[string]string{"playlistId": playlistID})
+	return apiErr(s.client.CreatePlaylistWithTracks(trackIDs, map[string]string{"playlistId": playlistID}))
 }
 
 func (s *subsonicMediaProvider) ClientDecidesScrobble() bool { return true }
 
 func (s *subsonicMediaProvider) TrackBeganPlayback(trackID string) error {
-	return s.client.Scrobble(trackID, map[string]string{
-		"time":       strconv.FormatInt(time.Now().UnixMilli(), 10),
-		"submission": "false"})
+	return s.TrackBeganPlaybackAt(trackID, time.Now())
+}
+
+func (s *subsonicMediaProvider) TrackEndedPlayback(trackID string, positionSecs int, submission bool) error {
+	return s.TrackEndedPlaybackAt(trackID, positionSecs, submission, time.Now())
 }
 
-func (s *subsonicMediaProvider) TrackEndedPlayback(trackID string, _ int, submission bool) error {
+// SupportsPlaybackStartTime interface
+var _ mediaprovider.SupportsPlaybackStartTime = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) TrackBeganPlaybackAt(trackID string, startTime time.Time) error {
+	return apiErr(s.client.Scrobble(trackID, map[string]string{
+		"time":       strconv.FormatInt(s.toServerTime(startTime).UnixMilli(), 10),
+		"submission": "false"}))
+}
+
+func (s *subsonicMediaProvider) TrackEndedPlaybackAt(trackID string, _ int, submission bool, startTime time.Time) error {
 	if !submission {
 		return nil
 	}
-	return s.client.Scrobble(trackID, map[string]string{
-		"time":       strconv.FormatInt(time.Now().UnixMilli(), 10),
-		"submission": "true"})
+	return apiErr(s.client.Scrobble(trackID, map[string]string{
+		"time":       strconv.FormatInt(s.toServerTime(startTime).UnixMilli(), 10),
+		"submission": "true"}))
 }
 
 func (s *subsonicMediaProvider) SetFavorite(params mediaprovider.RatingFavoriteParameters, favorite bool) error {
@@ -290,42 +882,48 @@ func (s *subsonicMediaProvider) SetFavorite(params mediaprovider.RatingFavoriteP
 		SongIDs:   params.TrackIDs,
 	}
 	if favorite {
-		return s.client.Star(subParams)
+		return apiErr(s.client.Star(subParams))
 	}
-	return s.client.Unstar(subParams)
+	return apiErr(s.client.Unstar(subParams))
 }
 
+// SetRating sets the rating of the given tracks, albums and artists.
+// The Subsonic API's setRating takes any item ID; all servers support
+// rating tracks, while album and artist ratings are supported by
+// some (e.g. Navidrome) and ignored or rejected by others.
 func (s *subsonicMediaProvider) SetRating(params mediaprovider.RatingFavoriteParameters, rating int) error {
 	// Subsonic doesn't allow bulk setting ratings.
-	// To not overwhelm the server with requests, set rating for
-	// only 5 tracks at a time concurrently
-	batchSize := 5
-	var err error
-	batchSetRating := func(offs int, wg *sync.WaitGroup) {
-		for i := 0; i < batchSize && offs+i < len(params.TrackIDs); i++ {
-			wg.Add(1)
-			go func(idx int) {
-				newErr := s.client.SetRating(params.TrackIDs[idx], rating)
-				if err == nil && newErr != nil {
-					err = newErr
-				}
-				wg.Done()
-			}(offs + i)
-		}
-	}
+	// To not overwhelm the server with requests, limit
+	// the number of concurrent requests
+	ids := ratingItemIDs(params)
+	errs := make([]error, len(ids))
+	helpers.RunConcurrently(context.Background(), len(ids), s.BatchConcurrency(), func(i int) {
+		errs[i] = apiErr(s.client.SetRating(ids[i], rating))
+	})
+	// each goroutine writes only its own slot, so no locking is needed,
+	// and failures are reported in item order regardless of timing
+	return errors.Join(errs...)
+}
 
-	numBatches := int(math.Ceil(float64(len(params.TrackIDs)) / float64(batchSize)))
-	for i := 0; i < numBatches; i++ {
-		var wg sync.WaitGroup
-		batchSetRating(i*batchSize, &wg)
-		wg.Wait()
-	}
+// ratingItemIDs returns all the item IDs in params, tracks first
+func ratingItemIDs(params mediaprovider.RatingFavoriteParameters) []string {
+	ids := make([]string, 0, len(params.TrackIDs)+len(params.AlbumIDs)+len(params.ArtistIDs))
+	ids = append(ids, params.TrackIDs...)
+	ids = append(ids, params.AlbumIDs...)
+	return append(ids, params.ArtistIDs...)
+}
 
-	return err
+// BatchConcurrency returns the max number of concurrent
+// requests to issue in batch operations.
+func (s *subsonicMediaProvider) BatchConcurrency() int {
+	if s.options.BatchConcurrency > 0 {
+		return s.options.BatchConcurrency
+	}
+	return helpers.DefaultBatchConcurrency
 }
 
 func (s *subsonicMediaProvider) CreateShareURL(id string) (*url.URL, error) {
-	share, err := s.client.CreateShare(id, nil)
+	share, err := apiResult(s.client.CreateShare(id, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -345,50 +943,108 @@ func (s *subsonicMediaProvider) CanShareArtists() bool {
 }
 
 func (s *subsonicMediaProvider) DownloadTrack(trackID string) (io.Reader, error) {
-	return s.client.Download(trackID)
+	return apiResult(s.client.Download(trackID))
 }
 
 func (s *subsonicMediaProvider) RescanLibrary() error {
-	_, err := s.client.StartScan()
+	_, err := s.RescanLibraryWithStatus()
+	return err
+}
+
+// SupportsScanStatus interface
+var _ mediaprovider.SupportsScanStatus = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) RescanLibraryWithStatus() (*mediaprovider.ScanStatus, error) {
+	st, err := apiResult(s.client.StartScan())
+	if err != nil {
+		return nil, err
+	}
+	return toScanStatus(st), nil
+}
+
+func (s *subsonicMediaProvider) GetScanStatus() (*mediaprovider.ScanStatus, error) {
+	st, err := apiResult(s.client.GetScanStatus())
+	if err != nil {
+		return nil, err
+	}
+	return toScanStatus(st), nil
+}
+
+func toScanStatus(st *subsonic.ScanStatus) *mediaprovider.ScanStatus {
+	if st == nil {
+		return &mediaprovider.ScanStatus{}
+	}
+	return &mediaprovider.ScanStatus{Scanning: st.Scanning, Count: st.Count}
+}
+
+// SupportsFullRescan interface
+var _ mediaprovider.SupportsFullRescan = (*subsonicMediaProvider)(nil)
+
+// FullRescanLibrary starts a scan with the fullScan parameter supported by
+// Navidrome and some other servers. Servers that don't support the parameter
+// ignore it and perform their regular scan.
+func (s *subsonicMediaProvider) FullRescanLibrary() error {
+	_, err := apiResult(s.client.Get("startScan", map[string]string{"fullScan": "true"}))
 	return err
 }
 
 // LyricsProvider interface
 var _ mediaprovider.LyricsProvider = (*subsonicMediaProvider)(nil)
 
+// GetLyrics returns the lyrics for the track. On OpenSubsonic servers, the
+// structured lyrics from getLyricsBySongId are preferred, which include lyrics
+// embedded in the file's tags, and may be synced. Otherwise, or if these are
+// empty, the legacy getLyrics endpoint is used, and then any lyrics the
+// server includes in the track's getSong response. Returns nil, nil if no
+// lyrics are found.
 func (s *subsonicMediaProvider) GetLyrics(track *mediaprovider.Track) (*mediaprovider.Lyrics, error) {
-	ext, err := s.client.GetOpenSubsonicExtensions()
-	supportsSynced := err == nil &&
-		slices.ContainsFunc(ext, func(ext *subsonic.OpenSubsonicExtension) bool {
-			return ext.Name == subsonic.SongLyricsExtension
-		})
-	if supportsSynced {
-		lyrics, err := s.client.GetLyricsBySongId(track.ID)
-		if err != nil || len(lyrics.StructuredLyrics) == 0 {
-			return nil, err
+	if s.hasExtension(subsonic.SongLyricsExtension) {
+		lyrics, err := s.getStructuredLyrics(track)
+		if lyrics != nil || err != nil {
+			return lyrics, err
 		}
-		lyric := lyrics.StructuredLyrics[0]
-		mpLyrics := &mediaprovider.Lyrics{
-			Title:  lyric.DisplayTitle,
-			Artist: lyric.DisplayArtist,
-			Synced: lyric.Synced,
-		}
-		for _, line := range lyric.Lines {
-			// Navidrome's incorrect lyric text field
-			// TODO: remove this after Navidrome 0.53.0 release.
-			text := line.Value
-			if text == "" {
-				text = line.Text
-			}
-			mpLyrics.Lines = append(mpLyrics.Lines, mediaprovider.LyricLine{
-				Text:  text,
-				Start: float64(line.Start) / 1000,
-			})
+	}
+	lyrics, err := s.getLegacyLyrics(track)
+	if lyrics != nil {
+		return lyrics, nil
+	}
+	if embedded := s.getEmbeddedLyrics(track); embedded != nil {
+		return embedded, nil
+	}
+	return nil, err
+}
+
+func (s *subsonicMediaProvider) getStructuredLyrics(track *mediaprovider.Track) (*mediaprovider.Lyrics, error) {
+	lyrics, err := apiResult(s.client.GetLyricsBySongId(track.ID))
+	if err != nil || lyrics == nil || len(lyrics.StructuredLyrics) == 0 {
+		return nil, err
+	}
+	lyric := lyrics.StructuredLyrics[0]
+	mpLyrics := &mediaprovider.Lyrics{
+		Title:  lyric.DisplayTitle,
+		Artist: lyric.DisplayArtist,
+		Synced: lyric.Synced,
+	}
+	for _, line := range lyric.Lines {
+		// Navidrome's incorrect lyric text field
+		// TODO: remove this after Navidrome 0.53.0 release.
+		text := line.Value
+		if text == "" {
+			text = line.Text
 		}
-		return mpLyrics, nil
+		mpLyrics.Lines = append(mpLyrics.Lines, mediaprovider.LyricLine{
+			Text:  text,
+			Start: float64(line.Start) / 1000,
+		})
 	}
-	// fallback to legacy getLyrics endpoint
-	lyrics, err := s.client.GetLyrics(track.Title, track.ArtistNames[0])
+	return mpLyrics, nil
+}
+
+func (s *subsonicMediaProvider) getLegacyLyrics(track *mediaprovider.Track) (*mediaprovider.Lyrics, error) {
+	if len(track.ArtistNames) == 0 {
+		return nil, nil
+	}
+	lyrics, err := apiResult(s.client.GetLyrics(track.Title, track.ArtistNames[0]))
 	if err != nil || lyrics == nil || lyrics.Text == "" {
 		return nil, err
 	}
@@ -406,6 +1062,44 @@ func (s *subsonicMediaProvider) GetLyrics(track *mediaprovider.Track) (*mediapro
 	return mpLyrics, nil
 }
 
+// The go-subsonic Child type doesn't decode the lyrics some servers
+// include in getSong responses, so they are decoded into this.
+// Depending on the server, they may be an attribute or an element.
+type songLyricsResponse struct {
+	Song struct {
+		LyricsAttr string `xml:"lyrics,attr"`
+		Lyrics     string `xml:"lyrics"`
+	} `xml:"song"`
+}
+
+// getEmbeddedLyrics returns the unsynced lyrics from the track's getSong
+// response, or nil if there are none or they couldn't be fetched.
+func (s *subsonicMediaProvider) getEmbeddedLyrics(track *mediaprovider.Track) *mediaprovider.Lyrics {
+	var resp songLyricsResponse
+	if err := s.getRaw("getSong", map[string]string{"id": track.ID}, &resp); err != nil {
+		return nil
+	}
+	text := resp.Song.Lyrics
+	if text == "" {
+		text = resp.Song.LyricsAttr
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	lyrics := &mediaprovider.Lyrics{
+		Title: track.Title,
+	}
+	if len(track.ArtistNames) > 0 {
+		lyrics.Artist = track.ArtistNames[0]
+	}
+	for _, line := range strings.Split(text, "\n") {
+		lyrics.Lines = append(lyrics.Lines, mediaprovider.LyricLine{
+			Text: line,
+		})
+	}
+	return lyrics
+}
+
 // CanSavePlayQueue interface
 var _ mediaprovider.CanSavePlayQueue = (*subsonicMediaProvider)(nil)
 
@@ -418,19 +1112,19 @@ func (s *subsonicMediaProvider) SavePlayQueue(trackIDs []string, currentTrackIdx
 		params["position"] = strconv.Itoa(timeSeconds * 1000)
 		params["current"] = trackIDs[currentTrackIdx]
 	}
-	return s.client.SavePlayQueue(trackIDs, params)
+	return apiErr(s.client.SavePlayQueue(trackIDs, params))
 }
 
 func (s *subsonicMediaProvider) GetPlayQueue() (*mediaprovider.SavedPlayQueue, error) {
-	pq, err := s.client.GetPlayQueue()
+	pq, err := apiResult(s.client.GetPlayQueue())
 	if err != nil {
 		return nil, err
 	}
 
-	savedQueue := &mediaprovider.SavedPlayQueue{}
 	if pq == nil {
-		return savedQueue, nil
+		return nil, nil
 	}
+	savedQueue := &mediaprovider.SavedPlayQueue{}
 	savedQueue.Tracks = sharedutil.MapSlice(pq.Entries, toTrack)
 	savedQueue.TrackPos = slices.IndexFunc(pq.Entries, func(e *subsonic.Child) bool {
 		return e.ID == pq.Current
@@ -439,6 +1133,92 @@ func (s *subsonicMediaProvider) GetPlayQueue() (*mediaprovider.SavedPlayQueue, e
 	return savedQueue, nil
 }
 
+// SupportsMusicFolderScoping interface
+var _ mediaprovider.SupportsMusicFolderScoping = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetRandomTracksInMusicFolder(genreName string, count int, musicFolderID string) ([]*mediaprovider.Track, error) {
+	return s.getRandomSongs(genreName, count, musicFolderID)
+}
+
+// getRandomSongs fetches random songs, optionally restricted to a genre
+// and music folder ("" for all folders).
+func (s *subsonicMediaProvider) getRandomSongs(genreName string, count int, musicFolderID string) ([]*mediaprovider.Track, error) {
+	opts := map[string]string{"size": strconv.Itoa(count)}
+	if genreName != "" {
+		opts["genre"] = genreName
+	}
+	if musicFolderID != "" {
+		opts["musicFolderId"] = musicFolderID
+	}
+	tr, err := apiResult(s.client.GetRandomSongs(opts))
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(tr, toTrack), nil
+}
+
+func (s *subsonicMediaProvider) GetGenreTracks(genreName string, count int, musicFolderID string) ([]*mediaprovider.Track, error) {
+	opts := map[string]string{"count": strconv.Itoa(count)}
+	if musicFolderID != "" {
+		opts["musicFolderId"] = musicFolderID
+	}
+	tr, err := apiResult(s.client.GetSongsByGenre(genreName, opts))
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(tr, toTrack), nil
+}
+
+// SupportsRandomAlbum interface
+var _ mediaprovider.SupportsRandomAlbum = (*subsonicMediaProvider)(nil)
+
+// GetRandomAlbum returns ErrNotSupported for a filter on explicit content,
+// since the OpenSubsonic explicitStatus isn't decoded by the go-subsonic client.
+func (s *subsonicMediaProvider) GetRandomAlbum(filter mediaprovider.AlbumFilter) (*mediaprovider.AlbumWithTracks, error) {
+	if filter != nil && filter.Options().ExcludeExplicit {
+		return nil, mediaprovider.ErrNotSupported
+	}
+	for i := 0; i < randomAlbumMaxAttempts; i++ {
+		al, err := apiResult(s.client.GetAlbumList2("random", s.withMusicFolder(map[string]string{"size": "1"})))
+		if err != nil {
+			return nil, err
+		}
+		if len(al) == 0 {
+			break // empty library
+		}
+		if filter == nil || filter.Matches(toAlbum(al[0])) {
+			return s.GetAlbum(al[0].ID)
+		}
+	}
+	return nil, mediaprovider.ErrNoMatchingAlbum
+}
+
+// SupportsServerMessage interface
+var _ mediaprovider.SupportsServerMessage = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetServerMessage() (string, error) {
+	if s.options.AnnouncementSender == "" {
+		return "", nil
+	}
+	msgs, err := s.getChatMessages(map[string]string{})
+	if err != nil {
+		return "", err
+	}
+	var latest *subsonic.ChatMessage
+	for _, m := range msgs {
+		if m.Username != s.options.AnnouncementSender {
+			continue
+		}
+		if latest == nil || m.Time > latest.Time {
+			latest = m
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Message, nil
+}
+
 // RadioProvider interface
 var _ mediaprovider.RadioProvider = (*subsonicMediaProvider)(nil)
 
@@ -447,17 +1227,18 @@ func (s *subsonicMediaProvider) GetRadioStations() ([]*mediaprovider.RadioStatio
 		return s.radiosCached, nil
 	}
 
-	rs, err := s.client.GetInternetRadioStations()
-	if err != nil {
+	// the go-subsonic InternetRadioStation type doesn't decode the
+	// station ID, which is needed to update or delete the station
+	var resp internetRadioStationsResponse
+	if err := s.getRaw("getInternetRadioStations", nil, &resp); err != nil {
 		return nil, err
 	}
-	s.radiosCached = sharedutil.MapSlice(rs, func(rs *subsonic.InternetRadioStation) *mediaprovider.RadioStation {
+	s.radiosCached = sharedutil.MapSlice(resp.Stations.Station, func(rs *internetRadioStation) *mediaprovider.RadioStation {
 		return &mediaprovider.RadioStation{
-			// TODO - subsonic library is missing ID in its radiostation object. add it
-			ID:          "radio-" + strings.ReplaceAll(rs.Name, " ", ""),
+			ID:          rs.ID,
 			Name:        rs.Name,
-			HomePageURL: rs.HomePageUrl,
-			StreamURL:   rs.StreamUrl,
+			HomePageURL: rs.HomePageURL,
+			StreamURL:   rs.StreamURL,
 		}
 	})
 	s.radiosCachedAt = time.Now().Unix()
@@ -478,6 +1259,62 @@ func (s *subsonicMediaProvider) GetRadioStation(id string) (*mediaprovider.Radio
 	return rs[index], nil
 }
 
+// SupportsRadioManagement interface
+var _ mediaprovider.SupportsRadioManagement = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) CreateRadioStation(name, streamURL, homepageURL string) error {
+	if name == "" {
+		return &mediaprovider.ValidationError{Field: "name", Reason: "must not be empty"}
+	}
+	if streamURL == "" {
+		return &mediaprovider.ValidationError{Field: "streamURL", Reason: "must not be empty"}
+	}
+	s.radiosCached = nil
+	_, err := apiResult(s.client.Get("createInternetRadioStation", radioStationParams(name, streamURL, homepageURL)))
+	return err
+}
+
+func (s *subsonicMediaProvider) UpdateRadioStation(id, name, streamURL, homepageURL string) error {
+	if name == "" {
+		return &mediaprovider.ValidationError{Field: "name", Reason: "must not be empty"}
+	}
+	if streamURL == "" {
+		return &mediaprovider.ValidationError{Field: "streamURL", Reason: "must not be empty"}
+	}
+	params := radioStationParams(name, streamURL, homepageURL)
+	params["id"] = id
+	s.radiosCached = nil
+	_, err := apiResult(s.client.Get("updateInternetRadioStation", params))
+	return err
+}
+
+func (s *subsonicMediaProvider) DeleteRadioStation(id string) error {
+	s.radiosCached = nil
+	_, err := apiResult(s.client.Get("deleteInternetRadioStation", map[string]string{"id": id}))
+	return err
+}
+
+func radioStationParams(name, streamURL, homepageURL string) map[string]string {
+	params := map[string]string{"name": name, "streamUrl": streamURL}
+	if homepageURL != "" {
+		params["homepageUrl"] = homepageURL
+	}
+	return params
+}
+
+type internetRadioStationsResponse struct {
+	Stations struct {
+		Station []*internetRadioStation `xml:"internetRadioStation"`
+	} `xml:"internetRadioStations"`
+}
+
+type internetRadioStation struct {
+	ID          string `xml:"id,attr"`
+	Name        string `xml:"name,attr"`
+	StreamURL   string `xml:"streamUrl,attr"`
+	HomePageURL string `xml:"homePageUrl,attr"`
+}
+
 func toTrack(ch *subsonic.Child) *mediaprovider.Track {
 	if ch == nil {
 		return nil
@@ -494,12 +1331,14 @@ func toTrack(ch *subsonic.Child) *mediaprovider.Track {
 		artistIDs = append(artistIDs, ch.ArtistID)
 	}
 
-	var rGain mediaprovider.ReplayGainInfo
+	var rGain *mediaprovider.ReplayGainInfo
 	if rg := ch.ReplayGain; rg != nil {
-		rGain.AlbumGain = rg.AlbumGain
-		rGain.TrackGain = rg.TrackGain
-		rGain.AlbumPeak = rg.AlbumPeak
-		rGain.TrackPeak = rg.TrackPeak
+		rGain = &mediaprovider.ReplayGainInfo{
+			AlbumGain: rg.AlbumGain,
+			TrackGain: rg.TrackGain,
+			AlbumPeak: rg.AlbumPeak,
+			TrackPeak: rg.TrackPeak,
+		}
 	}
 	var genres []string
 	if len(ch.Genres) > 0 {
@@ -542,6 +1381,7 @@ func toTrack(ch *subsonic.Child) *mediaprovider.Track {
 		FilePath:      ch.Path,
 		Size:          ch.Size,
 		BitRate:       ch.BitRate,
+		SampleRate:    ch.SamplingRate,
 		ContentType:   ch.ContentType,
 		Comment:       ch.Comment,
 		BPM:           ch.BPM,
@@ -607,6 +1447,8 @@ func fillAlbum(subAlbum *subsonic.AlbumID3, album *mediaprovider.Album) {
 	album.TrackCount = subAlbum.SongCount
 	album.Genres = genres
 	album.Favorite = !subAlbum.Starred.IsZero()
+	album.PlayCount = int(subAlbum.PlayCount)
+	album.Created = subAlbum.Created
 	album.ReleaseTypes = normalizeReleaseTypes(subAlbum.ReleaseTypes)
 	if subAlbum.IsCompilation {
 		album.ReleaseTypes |= mediaprovider.ReleaseTypeCompilation
@@ -688,10 +1530,14 @@ func fillPlaylist(pl *subsonic.Playlist, playlist *mediaprovider.Playlist) {
 	playlist.Public = pl.Public
 	playlist.TrackCount = pl.SongCount
 	playlist.Duration = pl.Duration
+	playlist.ChangedSince = pl.Changed
+	if playlist.ChangedSince.IsZero() {
+		playlist.ChangedSince = pl.Created
+	}
 }
 
 func (s *subsonicMediaProvider) GetSongRadio(trackID string, count int) ([]*mediaprovider.Track, error) {
-	tr, err := s.client.GetSimilarSongs(trackID, map[string]string{"count": strconv.Itoa(count)})
+	tr, err := apiResult(s.client.GetSimilarSongs(trackID, map[string]string{"count": strconv.Itoa(count)}))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,386 @@
+package subsonic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+func TestRatingItemIDs(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		params mediaprovider.RatingFavoriteParameters
+		want   []string
+	}{
+		{
+			name:   "tracks",
+			params: mediaprovider.RatingFavoriteParameters{TrackIDs: []string{"t1", "t2"}},
+			want:   []string{"t1", "t2"},
+		},
+		{
+			name:   "albums",
+			params: mediaprovider.RatingFavoriteParameters{AlbumIDs: []string{"al1"}},
+			want:   []string{"al1"},
+		},
+		{
+			name:   "artists",
+			params: mediaprovider.RatingFavoriteParameters{ArtistIDs: []string{"ar1", "ar2"}},
+			want:   []string{"ar1", "ar2"},
+		},
+		{
+			name: "mixed",
+			params: mediaprovider.RatingFavoriteParameters{
+				TrackIDs:  []string{"t1"},
+				AlbumIDs:  []string{"al1"},
+				ArtistIDs: []string{"ar1"},
+			},
+			want: []string{"t1", "al1", "ar1"},
+		},
+		{
+			name: "empty",
+			want: []string{},
+		},
+	} {
+		if got := ratingItemIDs(tc.params); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExistsResult(t *testing.T) {
+	notFound := errors.New("Error #70: Album not found")
+	if ok, err := existsResult(false, notFound); ok || err != nil {
+		t.Errorf("not found: got %v, %v", ok, err)
+	}
+	other := errors.New("connection refused")
+	if ok, err := existsResult(false, other); ok || err != other {
+		t.Errorf("transport error: got %v, %v", ok, err)
+	}
+	if ok, err := existsResult(true, nil); !ok || err != nil {
+		t.Errorf("found: got %v, %v", ok, err)
+	}
+}
+
+func TestIterateAlbums_AllSortOrders(t *testing.T) {
+	s := &subsonicMediaProvider{}
+	filter := mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{})
+	for _, sort := range s.AlbumSortOrders() {
+		if s.IterateAlbums(sort, filter) == nil {
+			t.Errorf("sort order %q is not handled by IterateAlbums", sort)
+		}
+	}
+}
+
+func TestIterateAlbums_ListType(t *testing.T) {
+	for sort, want := range map[string]string{
+		mediaprovider.AlbumSortRecentlyAdded:    "newest",
+		mediaprovider.AlbumSortRecentlyPlayed:   "recent",
+		mediaprovider.AlbumSortFrequentlyPlayed: "frequent",
+		mediaprovider.AlbumSortTitleAZ:          "alphabeticalByName",
+		mediaprovider.AlbumSortArtistAZ:         "alphabeticalByArtist",
+		mediaprovider.AlbumSortHighestRated:     "highest",
+		mediaprovider.AlbumSortFavorites:        "starred",
+	} {
+		var got []string
+		s := newTestProvider(t, func(endpoint string, params url.Values) string {
+			if endpoint == "getAlbumList2" {
+				got = append(got, params.Get("type"))
+			}
+			return "<albumList2/>"
+		})
+		iter := s.IterateAlbums(sort, mediaprovider.NewAlbumFilter(mediaprovider.AlbumFilterOptions{}))
+		if al := iter.Next(); al != nil {
+			t.Errorf("%s: got album %+v from an empty list", sort, al)
+		}
+		if len(got) == 0 || got[0] != want {
+			t.Errorf("%s: got list types %v, want %q", sort, got, want)
+		}
+	}
+}
+
+func TestAPIErr(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "nil"},
+		{name: "not found", err: errors.New("Error #70: not found")},
+		{name: "network", err: errors.New("dial tcp: connection refused")},
+		{name: "wrong credentials", err: errors.New("Error #40: wrong"), wantErr: mediaprovider.ErrAuthFailed},
+		{name: "not authorized", err: errors.New("Error #50: denied\n"), wantErr: mediaprovider.ErrNotAuthorized},
+	} {
+		err := apiErr(tc.err)
+		if tc.wantErr == nil {
+			if err != tc.err {
+				t.Errorf("%s: got error %v, want unchanged %v", tc.name, err, tc.err)
+			}
+			continue
+		}
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: got error %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if again := apiErr(err); again != err {
+			t.Errorf("%s: apiErr not idempotent, got %v", tc.name, again)
+		}
+	}
+	if !mediaprovider.IsAuthError(fmt.Errorf("wrapped: %w", mediaprovider.ErrAuthFailed)) {
+		t.Error("expected wrapped ErrAuthFailed to be an auth error")
+	}
+}
+
+func TestPlaylistTrackPositions(t *testing.T) {
+	entries := []*subsonic.Child{
+		{ID: "1", Title: "One", Duration: 60},
+		{ID: "2"}, // no longer available on the server
+		{ID: "3", Title: "Three", Duration: 60},
+	}
+	s := &subsonicMediaProvider{}
+	if got, want := s.playlistTrackPositions(entries), []int{0, 2}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	s.options.KeepMissingTracks = true
+	if got, want := s.playlistTrackPositions(entries), []int{0, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("KeepMissingTracks: got %v, want %v", got, want)
+	}
+}
+
+// newTestProvider returns a provider backed by a fake server. respond
+// returns the body of the subsonic-response document for each request.
+func newTestProvider(t *testing.T, respond func(endpoint string, params url.Values) string) *subsonicMediaProvider {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := respond(path.Base(r.URL.Path), r.URL.Query())
+		fmt.Fprintf(w, `<subsonic-response xmlns="http://subsonic.org/restapi" status="ok" version="1.16.1">%s</subsonic-response>`, body)
+	}))
+	t.Cleanup(srv.Close)
+	client := &subsonic.Client{Client: srv.Client(), BaseUrl: srv.URL, User: "test"}
+	return SubsonicMediaProvider(client).(*subsonicMediaProvider)
+}
+
+func TestGetEmbeddedLyrics(t *testing.T) {
+	track := &mediaprovider.Track{ID: "1", Title: "Song", ArtistNames: []string{"Artist"}}
+	for _, tc := range []struct {
+		name string
+		song string
+		want []string
+	}{
+		{name: "attribute", song: `<song id="1" lyrics="one&#xA;two"/>`, want: []string{"one", "two"}},
+		{name: "element", song: `<song id="1"><lyrics>one
+two</lyrics></song>`, want: []string{"one", "two"}},
+		{name: "none", song: `<song id="1"/>`},
+	} {
+		s := newTestProvider(t, func(endpoint string, _ url.Values) string {
+			if endpoint != "getSong" {
+				t.Errorf("%s: unexpected request to %s", tc.name, endpoint)
+			}
+			return tc.song
+		})
+		lyrics := s.getEmbeddedLyrics(track)
+		if tc.want == nil {
+			if lyrics != nil {
+				t.Errorf("%s: got %v, want nil", tc.name, lyrics)
+			}
+			continue
+		}
+		if lyrics == nil {
+			t.Errorf("%s: got nil lyrics", tc.name)
+			continue
+		}
+		var got []string
+		for _, l := range lyrics.Lines {
+			got = append(got, l.Text)
+		}
+		if !slices.Equal(got, tc.want) || lyrics.Artist != "Artist" {
+			t.Errorf("%s: got %v by %q, want %v", tc.name, got, lyrics.Artist, tc.want)
+		}
+	}
+}
+
+func TestGetAlbumList2_Played(t *testing.T) {
+	s := newTestProvider(t, func(endpoint string, params url.Values) string {
+		if endpoint != "getAlbumList2" || params.Get("type") != "recent" {
+			t.Errorf("unexpected request to %s with type %q", endpoint, params.Get("type"))
+		}
+		return `<albumList2>
+			<album id="1" name="One" played="2024-05-01T10:00:00Z" created="2020-01-01T00:00:00Z"/>
+			<album id="2" name="Two" created="2020-01-01T00:00:00Z"/>
+		</albumList2>`
+	})
+	albums, err := s.getAlbumList2("recent", map[string]string{"size": "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(albums) != 2 || albums[0].ID != "1" || albums[0].Name != "One" {
+		t.Fatalf("got %+v", albums)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !albums[0].LastPlayed.Equal(want) {
+		t.Errorf("LastPlayed: got %v, want %v", albums[0].LastPlayed, want)
+	}
+	if !albums[1].LastPlayed.IsZero() {
+		t.Errorf("LastPlayed: got %v for an album without a played attribute", albums[1].LastPlayed)
+	}
+}
+
+func TestVerifyEmptyFavorites(t *testing.T) {
+	s := newTestProvider(t, func(endpoint string, params url.Values) string {
+		switch endpoint {
+		case "getAlbumList2":
+			// one full page, then a partial one
+			n := favoritesVerifyPageSize
+			if params.Get("offset") != "0" {
+				n = 1
+			}
+			var sb strings.Builder
+			sb.WriteString("<albumList2>")
+			for i := 0; i < n; i++ {
+				fmt.Fprintf(&sb, `<album id="%s-%d" name="A" starred="2024-01-01T00:00:00Z"/>`, params.Get("offset"), i)
+			}
+			sb.WriteString("</albumList2>")
+			return sb.String()
+		case "getArtists":
+			return `<artists><index name="A">
+				<artist id="ar1" name="A1" starred="2024-01-01T00:00:00Z"/>
+				<artist id="ar2" name="A2"/>
+			</index></artists>`
+		}
+		t.Errorf("unexpected request to %s", endpoint)
+		return ""
+	})
+	var favs mediaprovider.Favorites
+	if err := s.verifyEmptyFavorites(&favs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(favs.Albums) != favoritesVerifyPageSize+1 {
+		t.Errorf("got %d albums, want %d", len(favs.Albums), favoritesVerifyPageSize+1)
+	}
+	if len(favs.Artists) != 1 || favs.Artists[0].ID != "ar1" || !favs.Artists[0].Favorite {
+		t.Errorf("got artists %+v, want only ar1", favs.Artists)
+	}
+}
+
+func TestGetNowPlaying(t *testing.T) {
+	s := newTestProvider(t, func(endpoint string, _ url.Values) string {
+		if endpoint != "getNowPlaying" {
+			t.Errorf("unexpected request to %s", endpoint)
+		}
+		return `<nowPlaying>
+			<entry id="t1" title="Song" album="Album" artist="Artist" duration="200"
+				username="alice" minutesAgo="3" playerId="1" playerName="web"/>
+		</nowPlaying>`
+	})
+	entries, err := s.GetNowPlaying()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Track.ID != "t1" || e.Track.Title != "Song" {
+		t.Errorf("got track %+v", e.Track)
+	}
+	if e.Username != "alice" || e.PlayerName != "web" || e.MinutesAgo != 3 {
+		t.Errorf("got entry %+v", e)
+	}
+}
+
+func TestDownloadPlaylist_WaitsForClose(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	logEvent := func(e string) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Base(r.URL.Path) {
+		case "getPlaylist":
+			fmt.Fprint(w, `<subsonic-response xmlns="http://subsonic.org/restapi" status="ok" version="1.16.1">
+				<playlist id="p1" name="P" songCount="2">
+					<entry id="t1" title="One"/>
+					<entry id="t2" title="Two"/>
+				</playlist></subsonic-response>`)
+		case "download":
+			logEvent("download " + r.URL.Query().Get("id"))
+			w.Header().Set("Content-Type", "audio/mpeg")
+			fmt.Fprint(w, "data")
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client := &subsonic.Client{Client: srv.Client(), BaseUrl: srv.URL, User: "test"}
+	s := SubsonicMediaProvider(client).(*subsonicMediaProvider)
+
+	ch, err := s.DownloadPlaylist(context.Background(), "p1", false, 0, "", func(done, _ int) {
+		logEvent(fmt.Sprintf("progress %d", done))
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for dl := range ch {
+		if dl.Err != nil {
+			t.Fatalf("unexpected error downloading %s: %v", dl.Track.ID, dl.Err)
+		}
+		logEvent("close " + dl.Track.ID)
+		dl.Reader.Close()
+	}
+	want := []string{"download t1", "close t1", "progress 1", "download t2", "close t2", "progress 2"}
+	if !slices.Equal(events, want) {
+		t.Errorf("got events %v, want %v", events, want)
+	}
+}
+
+func TestGetServerMessage(t *testing.T) {
+	s := newTestProvider(t, func(endpoint string, _ url.Values) string {
+		if endpoint != "getChatMessages" {
+			t.Errorf("unexpected request to %s", endpoint)
+		}
+		return `<chatMessages>
+			<chatMessage username="admin" time="100" message="maintenance tonight"/>
+			<chatMessage username="bob" time="200" message="hi all"/>
+			<chatMessage username="admin" time="50" message="old news"/>
+		</chatMessages>`
+	})
+	if msg, err := s.GetServerMessage(); err != nil || msg != "" {
+		t.Errorf("no sender configured: got %q, %v; want empty", msg, err)
+	}
+	s.options.AnnouncementSender = "admin"
+	if msg, err := s.GetServerMessage(); err != nil || msg != "maintenance tonight" {
+		t.Errorf("got %q, %v; want %q", msg, err, "maintenance tonight")
+	}
+}
+
+func TestGetRandomAlbum_MusicFolder(t *testing.T) {
+	s := newTestProvider(t, func(endpoint string, params url.Values) string {
+		switch endpoint {
+		case "getAlbumList2":
+			if got := params.Get("musicFolderId"); got != "f1" {
+				t.Errorf("musicFolderId: got %q, want %q", got, "f1")
+			}
+			return `<albumList2><album id="a1" name="A"/></albumList2>`
+		case "getAlbum":
+			return `<album id="a1" name="A"/>`
+		}
+		t.Errorf("unexpected request to %s", endpoint)
+		return ""
+	})
+	s.activeMusicFolderID = "f1"
+	al, err := s.GetRandomAlbum(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if al.ID != "a1" {
+		t.Errorf("got album %q, want %q", al.ID, "a1")
+	}
+}
@@ -7,11 +7,24 @@ import (
 
 type SubsonicServer struct {
 	subsonicCli.Client
+
+	// Options for the media provider returned by MediaProvider
+	ProviderOptions Options
+
+	// salts and tokens for stream URLs, generated at login
+	// if enabled by ProviderOptions (see Options.AuthSaltLength)
+	streamTokens []saltToken
 }
 
 func (s *SubsonicServer) Login(username, password string) mediaprovider.LoginResponse {
 	s.User = username
 	err := s.Client.Authenticate(password)
+	s.streamTokens = nil
+	if err == nil && !s.PasswordAuth {
+		if n := s.streamTokenCount(); n > 0 {
+			s.streamTokens = generateStreamTokens(password, s.ProviderOptions.AuthSaltLength, n)
+		}
+	}
 	return mediaprovider.LoginResponse{
 		Error:       err,
 		IsAuthError: err == subsonicCli.ErrAuthenticationFailure,
@@ -19,5 +32,21 @@ func (s *SubsonicServer) Login(username, password string) mediaprovider.LoginRes
 }
 
 func (s *SubsonicServer) MediaProvider() mediaprovider.MediaProvider {
-	return SubsonicMediaProvider(&s.Client)
+	mp := SubsonicMediaProviderWithOptions(&s.Client, s.ProviderOptions).(*subsonicMediaProvider)
+	mp.streamAuth.tokens = s.streamTokens
+	return mp
+}
+
+// streamTokenCount returns how many stream URL salts and tokens to generate
+// at login. Since the password isn't kept, AuthTokenTTL rotates through a
+// fixed pool of them rather than generating new ones.
+func (s *SubsonicServer) streamTokenCount() int {
+	switch {
+	case s.ProviderOptions.AuthTokenTTL > 0:
+		return streamAuthTokenPoolSize
+	case s.ProviderOptions.AuthSaltLength > 0:
+		return 1
+	default:
+		return 0
+	}
 }
@@ -0,0 +1,32 @@
+package subsonic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/backend/mediaprovider/helpers"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsBatchTrackLookup = (*subsonicMediaProvider)(nil)
+
+// GetTracks looks up the tracks with concurrent getSong calls,
+// as the Subsonic API has no endpoint to fetch songs by ID in bulk.
+func (s *subsonicMediaProvider) GetTracks(trackIDs []string) ([]*mediaprovider.Track, error) {
+	tracks := make([]*mediaprovider.Track, len(trackIDs))
+	errs := make([]error, len(trackIDs))
+	helpers.RunConcurrently(context.Background(), len(trackIDs), s.BatchConcurrency(), func(i int) {
+		tr, err := retryGet(context.Background(), s, func() (*subsonic.Child, error) {
+			return s.client.GetSong(trackIDs[i])
+		})
+		if err != nil {
+			if !isNotFoundErr(err) {
+				errs[i] = err
+			}
+			return
+		}
+		tracks[i] = toTrack(tr)
+	})
+	return tracks, errors.Join(errs...)
+}
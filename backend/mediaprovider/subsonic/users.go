@@ -0,0 +1,102 @@
+package subsonic
+
+import (
+	"strconv"
+
+	"github.com/dweymouth/supersonic/backend/mediaprovider"
+	"github.com/dweymouth/supersonic/sharedutil"
+	"github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+var _ mediaprovider.SupportsUserManagement = (*subsonicMediaProvider)(nil)
+
+func (s *subsonicMediaProvider) GetCurrentUser() (*mediaprovider.User, error) {
+	user, err := s.getCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	return toUser(user), nil
+}
+
+func (s *subsonicMediaProvider) GetUsers() ([]*mediaprovider.User, error) {
+	if err := s.checkAdmin(); err != nil {
+		return nil, err
+	}
+	users, err := apiResult(s.client.GetUsers())
+	if err != nil {
+		return nil, err
+	}
+	return sharedutil.MapSlice(users, toUser), nil
+}
+
+func (s *subsonicMediaProvider) CreateUser(params mediaprovider.CreateUserParams) error {
+	if params.Username == "" {
+		return &mediaprovider.ValidationError{Field: "username", Reason: "must not be empty"}
+	}
+	if params.Password == "" {
+		return &mediaprovider.ValidationError{Field: "password", Reason: "must not be empty"}
+	}
+	if params.Email == "" {
+		return &mediaprovider.ValidationError{Field: "email", Reason: "must not be empty"}
+	}
+	if err := s.checkAdmin(); err != nil {
+		return err
+	}
+	return apiErr(s.client.CreateUser(params.Username, params.Password, params.Email, map[string]string{
+		"adminRole":    strconv.FormatBool(params.AdminRole),
+		"settingsRole": strconv.FormatBool(params.SettingsRole),
+		"downloadRole": strconv.FormatBool(params.DownloadRole),
+		"uploadRole":   strconv.FormatBool(params.UploadRole),
+		"playlistRole": strconv.FormatBool(params.PlaylistRole),
+		"coverArtRole": strconv.FormatBool(params.CoverArtRole),
+		"commentRole":  strconv.FormatBool(params.CommentRole),
+		"podcastRole":  strconv.FormatBool(params.PodcastRole),
+		"streamRole":   strconv.FormatBool(params.StreamRole),
+		"jukeboxRole":  strconv.FormatBool(params.JukeboxRole),
+		"shareRole":    strconv.FormatBool(params.ShareRole),
+	}))
+}
+
+func (s *subsonicMediaProvider) DeleteUser(username string) error {
+	if username == "" {
+		return &mediaprovider.ValidationError{Field: "username", Reason: "must not be empty"}
+	}
+	if err := s.checkAdmin(); err != nil {
+		return err
+	}
+	return apiErr(s.client.DeleteUser(username))
+}
+
+// returns ErrNotAuthorized if the logged in user is not an admin
+func (s *subsonicMediaProvider) checkAdmin() error {
+	user, err := s.getCurrentUser()
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.AdminRole {
+		return mediaprovider.ErrNotAuthorized
+	}
+	return nil
+}
+
+func toUser(u *subsonic.User) *mediaprovider.User {
+	if u == nil {
+		return nil
+	}
+	return &mediaprovider.User{
+		Username:     u.Username,
+		Email:        u.Email,
+		MaxBitRate:   u.MaxBitRate,
+		AdminRole:    u.AdminRole,
+		SettingsRole: u.SettingsRole,
+		DownloadRole: u.DownloadRole,
+		UploadRole:   u.UploadRole,
+		PlaylistRole: u.PlaylistRole,
+		CoverArtRole: u.CoverArtRole,
+		CommentRole:  u.CommentRole,
+		PodcastRole:  u.PodcastRole,
+		StreamRole:   u.StreamRole,
+		JukeboxRole:  u.JukeboxRole,
+		ShareRole:    u.ShareRole,
+	}
+}
@@ -45,6 +45,7 @@ type playbackEngine struct {
 
 	playTimeStopwatch   util.Stopwatch
 	curTrackDuration    float64
+	curTrackStartTime   time.Time
 	latestTrackPosition float64 // cleared by checkScrobble
 	callbacksDisabled   bool
 
@@ -472,6 +473,7 @@ func (p *playbackEngine) handleOnTrackChange() {
 	p.isRadio = isRadio
 	p.wasStopped = false
 	p.curTrackDuration = float64(nowPlaying.Metadata().Duration)
+	p.curTrackStartTime = time.Now()
 	p.sendNowPlayingScrobble() // Must come before invokeOnChangeCallbacks b/c track may immediately be scrobbled
 	p.invokeOnSongChangeCallbacks()
 	p.doUpdateTimePos(false)
@@ -566,9 +568,23 @@ func (p *playbackEngine) setTrack(idx int, next bool) error {
 }
 
 func (p *playbackEngine) setNextTrack(idx int) error {
+	if idx >= 0 {
+		p.primeTrack(idx)
+	}
 	return p.setTrack(idx, true)
 }
 
+// warm up the server for the next track, if the server supports it
+func (p *playbackEngine) primeTrack(idx int) {
+	tr, ok := p.playQueue[idx].(*mediaprovider.Track)
+	if !ok {
+		return
+	}
+	if pr, ok := p.sm.Server.(mediaprovider.SupportsTrackPriming); ok {
+		_ = pr.PrimeNextTrack(tr.ID) // an optimization; errors are harmless
+	}
+}
+
 // call BEFORE updating p.nowPlayingIdx
 func (p *playbackEngine) checkScrobble() {
 	if !p.scrobbleCfg.Enabled || len(p.playQueue) == 0 || p.nowPlayingIdx < 0 {
@@ -594,7 +610,11 @@ func (p *playbackEngine) checkScrobble() {
 		p.lastScrobbled = track
 		submission = true
 	}
-	go server.TrackEndedPlayback(track.ID, int(p.latestTrackPosition), submission)
+	if s, ok := server.(mediaprovider.SupportsPlaybackStartTime); ok {
+		go s.TrackEndedPlaybackAt(track.ID, int(p.latestTrackPosition), submission, p.curTrackStartTime)
+	} else {
+		go server.TrackEndedPlayback(track.ID, int(p.latestTrackPosition), submission)
+	}
 	p.latestTrackPosition = 0
 	p.playTimeStopwatch.Reset()
 }
@@ -614,7 +634,11 @@ func (p *playbackEngine) sendNowPlayingScrobble() {
 		p.lastScrobbled = track
 		track.PlayCount += 1
 	}
-	go p.sm.Server.TrackBeganPlayback(track.ID)
+	if s, ok := server.(mediaprovider.SupportsPlaybackStartTime); ok {
+		go s.TrackBeganPlaybackAt(track.ID, p.curTrackStartTime)
+	} else {
+		go server.TrackBeganPlayback(track.ID)
+	}
 }
 
 // creates a deep copy of the track info so that we can maintain our own state
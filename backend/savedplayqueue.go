@@ -69,7 +69,9 @@ func LoadPlayQueue(filepath string, sm *ServerManager, loadFromServer bool) (*Sa
 	if pq, ok := sm.Server.(mediaprovider.CanSavePlayQueue); loadFromServer && ok && pq != nil {
 		// load queue from server
 		queue, err := pq.GetPlayQueue()
-		if err == nil {
+		if err == nil && queue == nil {
+			return &SavedPlayQueue{}, nil
+		} else if err == nil {
 			return &SavedPlayQueue{
 				Tracks:     queue.Tracks,
 				TrackIndex: queue.TrackPos,
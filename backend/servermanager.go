@@ -202,6 +202,11 @@ func (s *ServerManager) connect(connection ServerConnection, password string) (m
 		}
 	} else {
 		ua := fmt.Sprintf("%s/%s", s.appName, s.appVersion)
+		providerOpts := subsonicMP.Options{
+			AuthSaltLength:     s.config.Application.StreamAuthSaltLength,
+			AuthTokenTTL:       time.Duration(s.config.Application.StreamAuthTokenTTLSeconds) * time.Second,
+			AnnouncementSender: connection.AnnouncementSender,
+		}
 		cli = &subsonicMP.SubsonicServer{
 			Client: subsonic.Client{
 				UserAgent:    ua,
@@ -211,6 +216,7 @@ func (s *ServerManager) connect(connection ServerConnection, password string) (m
 				PasswordAuth: connection.LegacyAuth,
 				ClientName:   res.AppName,
 			},
+			ProviderOptions: providerOpts,
 		}
 		s.checkSetInsecureSkipVerify(cli.(*subsonicMP.SubsonicServer).Client.Client)
 		altCli = &subsonicMP.SubsonicServer{
@@ -222,6 +228,7 @@ func (s *ServerManager) connect(connection ServerConnection, password string) (m
 				PasswordAuth: connection.LegacyAuth,
 				ClientName:   res.AppName,
 			},
+			ProviderOptions: providerOpts,
 		}
 		s.checkSetInsecureSkipVerify(altCli.(*subsonicMP.SubsonicServer).Client.Client)
 	}
@@ -112,13 +112,15 @@ func (t *TrackInfoDialog) CreateRenderer() fyne.WidgetRenderer {
 		addFormRow(c, lang.L("Last played"), t.track.LastPlayed.Format(time.RFC1123))
 	}
 
-	if t.track.ReplayGain.TrackPeak > 0 {
-		addFormRow(c, lang.L("Track gain"), fmt.Sprintf("%0.2f dB", t.track.ReplayGain.TrackGain))
-		addFormRow(c, lang.L("Track peak"), fmt.Sprintf("%0.6f", t.track.ReplayGain.TrackPeak))
-	}
-	if t.track.ReplayGain.AlbumPeak > 0 {
-		addFormRow(c, lang.L("Album gain"), fmt.Sprintf("%0.2f dB", t.track.ReplayGain.AlbumGain))
-		addFormRow(c, lang.L("Album peak"), fmt.Sprintf("%0.6f", t.track.ReplayGain.AlbumPeak))
+	if rg := t.track.ReplayGain; rg != nil {
+		if rg.TrackPeak > 0 {
+			addFormRow(c, lang.L("Track gain"), fmt.Sprintf("%0.2f dB", rg.TrackGain))
+			addFormRow(c, lang.L("Track peak"), fmt.Sprintf("%0.6f", rg.TrackPeak))
+		}
+		if rg.AlbumPeak > 0 {
+			addFormRow(c, lang.L("Album gain"), fmt.Sprintf("%0.2f dB", rg.AlbumGain))
+			addFormRow(c, lang.L("Album peak"), fmt.Sprintf("%0.6f", rg.AlbumPeak))
+		}
 	}
 
 	title := widget.NewRichTextWithText(lang.L("Track Info"))